@@ -0,0 +1,284 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/jboewer/minesshweeper/game"
+	"io"
+	"net/http"
+)
+
+var (
+	ErrUnknownAction = errors.New("unknown action")
+	ErrBoardTooLarge = game.ErrBoardTooLarge
+)
+
+// NewRequest is the body of POST /games. Difficulty names a preset or a
+// custom board such as 20x10/30; alternatively the size can be given
+// field by field. Without either, the game is played on Beginner.
+type NewRequest struct {
+	Difficulty string `json:"difficulty,omitempty"`
+	Width      int    `json:"width,omitempty"`
+	Height     int    `json:"height,omitempty"`
+	Mines      int    `json:"mines,omitempty"`
+	Seed       *int64 `json:"seed,omitempty"`
+}
+
+// Action is the body of POST /games/{id}/actions. Type is one of reveal,
+// flag or chord.
+type Action struct {
+	Type string `json:"type"`
+	X    int    `json:"x"`
+	Y    int    `json:"y"`
+}
+
+// Game is the board as the player sees it. Mines are only shown once the
+// game is lost.
+type Game struct {
+	ID     string   `json:"id"`
+	Width  int      `json:"width"`
+	Height int      `json:"height"`
+	Mines  int      `json:"mines"`
+	Flags  int      `json:"flags"`
+	State  string   `json:"state"`
+	Cells  [][]Cell `json:"cells"`
+	Result *Result  `json:"result,omitempty"`
+}
+
+// Cell is one cell of the board. State is one of hidden, flagged, revealed
+// or mine; Adjacent is only meaningful for revealed cells.
+type Cell struct {
+	State     string `json:"state"`
+	Adjacent  int    `json:"adjacent"`
+	Exploded  bool   `json:"exploded,omitempty"`
+	WrongFlag bool   `json:"wrong_flag,omitempty"`
+}
+
+// Result sums up a finished game. The seed is only given away once the
+// game is over, since the mines could be worked out from it.
+type Result struct {
+	Won              bool    `json:"won"`
+	Seed             int64   `json:"seed"`
+	TimeMillis       int64   `json:"time_ms"`
+	Clicks           int     `json:"clicks"`
+	ThreeBV          int     `json:"3bv"`
+	ThreeBVPerSecond float64 `json:"3bv_per_second"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// NewHandler serves the games in store:
+//
+//	POST   /games              create a game from a NewRequest
+//	GET    /games/{id}         get the board
+//	POST   /games/{id}/actions play an Action
+//	DELETE /games/{id}         end the game
+func NewHandler(store *Store) http.Handler {
+	h := handler{store: store}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /games", h.create)
+	mux.HandleFunc("GET /games/{id}", h.get)
+	mux.HandleFunc("POST /games/{id}/actions", h.act)
+	mux.HandleFunc("DELETE /games/{id}", h.delete)
+
+	return mux
+}
+
+type handler struct {
+	store *Store
+}
+
+func (h handler) create(w http.ResponseWriter, r *http.Request) {
+	var req NewRequest
+	if err := decode(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	g, err := req.newGame()
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	id := h.store.Add(g)
+	writeJSON(w, http.StatusCreated, view(id, g))
+}
+
+func (req NewRequest) newGame() (*game.Game, error) {
+	d := game.Beginner
+	switch {
+	case req.Difficulty != "":
+		parsed, err := game.ParseDifficulty(req.Difficulty)
+		if err != nil {
+			return nil, err
+		}
+		d = parsed
+	case req.Width != 0 || req.Height != 0 || req.Mines != 0:
+		d = game.DifficultyOf(req.Width, req.Height, req.Mines)
+	}
+
+	if err := d.Validate(); err != nil {
+		return nil, err
+	}
+	if err := d.CheckSize(); err != nil {
+		return nil, err
+	}
+
+	var opts []game.Option
+	if req.Seed != nil {
+		opts = append(opts, game.WithSeed(*req.Seed))
+	}
+
+	return game.NewWithDifficulty(d, opts...)
+}
+
+func (h handler) get(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	g, err := h.store.Get(id)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, view(id, g))
+}
+
+func (h handler) act(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	g, err := h.store.Get(id)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	var a Action
+	if err := decode(r, &a); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := a.apply(g); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, view(id, g))
+}
+
+func (a Action) apply(g *game.Game) error {
+	if a.X < 0 || a.Y < 0 || a.X >= g.GetGridWidth() || a.Y >= g.GetGridHeight() {
+		return game.ErrOutOfBounds
+	}
+
+	var err error
+	switch a.Type {
+	case "reveal":
+		_, err = g.Reveal(a.X, a.Y)
+	case "flag":
+		g.ToggleFlag(a.X, a.Y)
+	case "chord":
+		_, err = g.Chord(a.X, a.Y)
+	default:
+		err = fmt.Errorf("%w %q", ErrUnknownAction, a.Type)
+	}
+
+	return err
+}
+
+func (h handler) delete(w http.ResponseWriter, r *http.Request) {
+	if err := h.store.Delete(r.PathValue("id")); err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func view(id string, g *game.Game) Game {
+	s := g.Snapshot()
+
+	v := Game{
+		ID:     id,
+		Width:  s.Width,
+		Height: s.Height,
+		Mines:  s.MineCount,
+		Flags:  s.FlagCount,
+		State:  stateName(s.State),
+		Cells:  make([][]Cell, s.Height),
+	}
+
+	for y, row := range s.Cells {
+		v.Cells[y] = make([]Cell, s.Width)
+		for x, c := range row {
+			v.Cells[y][x] = Cell{
+				State:     cellStateName(c.State),
+				Adjacent:  c.AdjacentMines,
+				Exploded:  c.Exploded,
+				WrongFlag: c.WrongFlag,
+			}
+		}
+	}
+
+	if s.State != game.StatePlaying {
+		stats := g.Stats()
+		v.Result = &Result{
+			Won:              s.State == game.StateWon,
+			Seed:             g.Seed(),
+			TimeMillis:       stats.Elapsed.Milliseconds(),
+			Clicks:           stats.Clicks,
+			ThreeBV:          stats.ThreeBV,
+			ThreeBVPerSecond: stats.ThreeBVPerSecond(),
+		}
+	}
+
+	return v
+}
+
+func stateName(s game.State) string {
+	switch s {
+	case game.StateWon:
+		return "won"
+	case game.StateLost:
+		return "lost"
+	default:
+		return "playing"
+	}
+}
+
+func cellStateName(s game.CellState) string {
+	switch s {
+	case game.CellStateFlagged:
+		return "flagged"
+	case game.CellStateRevealed:
+		return "revealed"
+	case game.CellStateMine:
+		return "mine"
+	default:
+		return "hidden"
+	}
+}
+
+// decode reads the JSON body of r into v. An empty body leaves v as it is.
+func decode(r *http.Request, v any) error {
+	dec := json.NewDecoder(http.MaxBytesReader(nil, r.Body, 1<<16))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("invalid request body: %w", err)
+	}
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}
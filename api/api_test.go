@@ -0,0 +1,187 @@
+package api_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/jboewer/minesshweeper/api"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type client struct {
+	t      *testing.T
+	server *httptest.Server
+}
+
+func newClient(t *testing.T, store *api.Store) client {
+	server := httptest.NewServer(api.NewHandler(store))
+	t.Cleanup(server.Close)
+
+	return client{t: t, server: server}
+}
+
+// do sends body as JSON and decodes the response into out, if given.
+func (c client) do(method, path string, body any, out any) int {
+	c.t.Helper()
+
+	var buf bytes.Buffer
+	if body != nil {
+		assert.NoError(c.t, json.NewEncoder(&buf).Encode(body))
+	}
+
+	req, err := http.NewRequest(method, c.server.URL+path, &buf)
+	assert.NoError(c.t, err)
+
+	res, err := c.server.Client().Do(req)
+	assert.NoError(c.t, err)
+	defer res.Body.Close()
+
+	if out != nil {
+		assert.NoError(c.t, json.NewDecoder(res.Body).Decode(out))
+	}
+
+	return res.StatusCode
+}
+
+func TestCreate(t *testing.T) {
+	c := newClient(t, api.NewStore(time.Hour))
+
+	t.Run("Default", func(t *testing.T) {
+		var g api.Game
+		status := c.do(http.MethodPost, "/games", nil, &g)
+
+		assert.Equal(t, http.StatusCreated, status)
+		assert.NotEmpty(t, g.ID)
+		assert.Equal(t, 9, g.Width)
+		assert.Equal(t, 10, g.Mines)
+		assert.Equal(t, "playing", g.State)
+		assert.Nil(t, g.Result)
+	})
+	t.Run("Difficulty", func(t *testing.T) {
+		var g api.Game
+		status := c.do(http.MethodPost, "/games", api.NewRequest{Difficulty: "expert"}, &g)
+
+		assert.Equal(t, http.StatusCreated, status)
+		assert.Equal(t, 30, g.Width)
+		assert.Equal(t, 16, g.Height)
+		assert.Equal(t, 99, g.Mines)
+	})
+	t.Run("Size", func(t *testing.T) {
+		var g api.Game
+		status := c.do(http.MethodPost, "/games", api.NewRequest{Width: 5, Height: 4, Mines: 3}, &g)
+
+		assert.Equal(t, http.StatusCreated, status)
+		assert.Len(t, g.Cells, 4)
+		assert.Len(t, g.Cells[0], 5)
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		for _, req := range []any{
+			api.NewRequest{Difficulty: "impossible"},
+			api.NewRequest{Width: 3, Height: 3, Mines: 9},
+			api.NewRequest{Width: 1000, Height: 1000, Mines: 1},
+			map[string]int{"size": 3},
+		} {
+			var res map[string]string
+			status := c.do(http.MethodPost, "/games", req, &res)
+
+			assert.Equal(t, http.StatusBadRequest, status, req)
+			assert.NotEmpty(t, res["error"], req)
+		}
+	})
+}
+
+func TestPlay(t *testing.T) {
+	c := newClient(t, api.NewStore(time.Hour))
+	seed := int64(1)
+
+	var g api.Game
+	c.do(http.MethodPost, "/games", api.NewRequest{Width: 5, Height: 5, Mines: 20, Seed: &seed}, &g)
+	path := "/games/" + g.ID
+
+	t.Run("Flag", func(t *testing.T) {
+		status := c.do(http.MethodPost, path+"/actions", api.Action{Type: "flag", X: 0, Y: 0}, &g)
+
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, 1, g.Flags)
+		assert.Equal(t, "flagged", g.Cells[0][0].State)
+
+		c.do(http.MethodPost, path+"/actions", api.Action{Type: "flag", X: 0, Y: 0}, &g)
+		assert.Equal(t, 0, g.Flags)
+	})
+	t.Run("Invalid actions", func(t *testing.T) {
+		for _, a := range []api.Action{
+			{Type: "dance"},
+			{Type: "reveal", X: 5},
+			{Type: "reveal", Y: -1},
+		} {
+			assert.Equal(t, http.StatusBadRequest, c.do(http.MethodPost, path+"/actions", a, nil), a)
+		}
+	})
+	t.Run("Mines stay hidden until the game is lost", func(t *testing.T) {
+		for i := 0; i < 25 && g.State == "playing"; i++ {
+			for _, row := range g.Cells {
+				for _, cell := range row {
+					assert.NotEqual(t, "mine", cell.State)
+					if cell.State == "hidden" {
+						assert.Zero(t, cell.Adjacent)
+					}
+				}
+			}
+			assert.Nil(t, g.Result)
+
+			status := c.do(http.MethodPost, path+"/actions", api.Action{Type: "reveal", X: i % 5, Y: i / 5}, &g)
+			assert.Equal(t, http.StatusOK, status)
+		}
+
+		assert.Equal(t, "lost", g.State)
+		assert.Equal(t, seed, g.Result.Seed)
+		assert.False(t, g.Result.Won)
+
+		mines := 0
+		for _, row := range g.Cells {
+			for _, cell := range row {
+				if cell.State == "mine" {
+					mines++
+				}
+			}
+		}
+		assert.Equal(t, 20, mines)
+	})
+	t.Run("Get", func(t *testing.T) {
+		var got api.Game
+		status := c.do(http.MethodGet, path, nil, &got)
+
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, g, got)
+	})
+	t.Run("Delete", func(t *testing.T) {
+		assert.Equal(t, http.StatusNoContent, c.do(http.MethodDelete, path, nil, nil))
+		assert.Equal(t, http.StatusNotFound, c.do(http.MethodGet, path, nil, nil))
+		assert.Equal(t, http.StatusNotFound, c.do(http.MethodDelete, path, nil, nil))
+		assert.Equal(t, http.StatusNotFound, c.do(http.MethodPost, path+"/actions", api.Action{Type: "reveal"}, nil))
+	})
+}
+
+func TestStore_Expiry(t *testing.T) {
+	store := api.NewStore(50 * time.Millisecond)
+	c := newClient(t, store)
+
+	var kept, expired api.Game
+	c.do(http.MethodPost, "/games", nil, &kept)
+	c.do(http.MethodPost, "/games", nil, &expired)
+
+	for i := 0; i < 3; i++ {
+		time.Sleep(30 * time.Millisecond)
+		assert.Equal(t, http.StatusOK, c.do(http.MethodGet, "/games/"+kept.ID, nil, nil))
+	}
+
+	assert.Equal(t, 1, store.Expire())
+	assert.Equal(t, 1, store.Len())
+	assert.Equal(t, http.StatusNotFound, c.do(http.MethodGet, "/games/"+expired.ID, nil, nil))
+
+	time.Sleep(60 * time.Millisecond)
+	assert.Equal(t, http.StatusNotFound, c.do(http.MethodGet, "/games/"+kept.ID, nil, nil))
+}
@@ -0,0 +1,112 @@
+package api
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"github.com/jboewer/minesshweeper/game"
+	"sync"
+	"time"
+)
+
+var (
+	ErrGameNotFound = errors.New("game not found")
+)
+
+// Store keeps the games of the API in memory. Games expire once they have
+// not been used for the store's TTL.
+type Store struct {
+	mu    sync.Mutex
+	ttl   time.Duration
+	games map[string]*entry
+}
+
+type entry struct {
+	game     *game.Game
+	lastUsed time.Time
+}
+
+func NewStore(ttl time.Duration) *Store {
+	return &Store{
+		ttl:   ttl,
+		games: map[string]*entry{},
+	}
+}
+
+// Add stores g and returns its ID. IDs are random, so knowing one game's ID
+// doesn't give away any other.
+func (s *Store) Add(g *game.Game) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := newID()
+	s.games[id] = &entry{game: g, lastUsed: time.Now()}
+
+	return id
+}
+
+// Get returns the game and keeps it from expiring for another TTL.
+func (s *Store) Get(id string) (*game.Game, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.games[id]
+	if !ok {
+		return nil, ErrGameNotFound
+	}
+	if s.expired(e, time.Now()) {
+		delete(s.games, id)
+		return nil, ErrGameNotFound
+	}
+
+	e.lastUsed = time.Now()
+	return e.game, nil
+}
+
+func (s *Store) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.games[id]; !ok {
+		return ErrGameNotFound
+	}
+	delete(s.games, id)
+
+	return nil
+}
+
+// Expire removes all expired games and returns how many there were.
+func (s *Store) Expire() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	expired := 0
+	for id, e := range s.games {
+		if s.expired(e, now) {
+			delete(s.games, id)
+			expired++
+		}
+	}
+
+	return expired
+}
+
+func (s *Store) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.games)
+}
+
+func (s *Store) expired(e *entry, now time.Time) bool {
+	return now.Sub(e.lastUsed) > s.ttl
+}
+
+func newID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
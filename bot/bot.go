@@ -0,0 +1,273 @@
+package bot
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/jboewer/minesshweeper/game"
+	"io"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrNoGame         = errors.New("no game, send new first")
+	ErrUnknownCommand = errors.New("unknown command")
+)
+
+// Response is sent for every command. Board is set whenever there is a
+// game, Result once it has ended.
+type Response struct {
+	OK     bool    `json:"ok"`
+	Error  string  `json:"error,omitempty"`
+	Board  *Board  `json:"board,omitempty"`
+	Result *Result `json:"result,omitempty"`
+}
+
+// Board is the board as the player sees it. Every row of Cells has one
+// character per cell:
+//
+//	#    hidden
+//	F    flagged
+//	0-8  revealed, with the number of adjacent mines
+//	*    mine, once the game is lost
+//	X    the mine that ended the game
+//	!    a flag on a safe cell, once the game is lost
+type Board struct {
+	Width  int      `json:"width"`
+	Height int      `json:"height"`
+	Mines  int      `json:"mines"`
+	Flags  int      `json:"flags"`
+	State  string   `json:"state"`
+	Cells  []string `json:"cells"`
+}
+
+// Result sums up a finished game. The seed is only given away once the
+// game is over, since the mines could be worked out from it.
+type Result struct {
+	Won              bool    `json:"won"`
+	Seed             int64   `json:"seed"`
+	TimeMillis       int64   `json:"time_ms"`
+	Clicks           int     `json:"clicks"`
+	ThreeBV          int     `json:"3bv"`
+	ThreeBVPerSecond float64 `json:"3bv_per_second"`
+}
+
+// Session is a single client speaking the bot protocol. It plays one game
+// at a time.
+type Session struct {
+	difficulty game.Difficulty
+	game       *game.Game
+	quit       bool
+}
+
+// NewSession returns a session whose games default to d.
+func NewSession(d game.Difficulty) *Session {
+	return &Session{difficulty: d}
+}
+
+// Serve speaks the bot protocol until the client quits or r is exhausted.
+// The client sends one command per line:
+//
+//	new [difficulty] [seed n]  start a game, on the default board unless a
+//	                           preset or WxH/M is given
+//	reveal x y                 open a cell
+//	flag x y                   toggle a flag
+//	chord x y                  open the neighbours of a satisfied number
+//	board                      show the board
+//	quit                       end the session
+//
+// Each command is answered with a Response as a single line of JSON.
+func Serve(r io.Reader, w io.Writer, d game.Difficulty) error {
+	s := NewSession(d)
+	enc := json.NewEncoder(w)
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		if err := enc.Encode(s.Handle(line)); err != nil {
+			return err
+		}
+		if s.quit {
+			return nil
+		}
+	}
+
+	return scanner.Err()
+}
+
+// Handle runs a single command.
+func (s *Session) Handle(line string) Response {
+	if err := s.handle(strings.Fields(line)); err != nil {
+		res := s.response()
+		res.OK = false
+		res.Error = err.Error()
+		return res
+	}
+	return s.response()
+}
+
+func (s *Session) handle(args []string) error {
+	if len(args) == 0 {
+		return ErrUnknownCommand
+	}
+
+	switch strings.ToLower(args[0]) {
+	case "new":
+		return s.newGame(args[1:])
+	case "reveal", "flag", "chord":
+		return s.move(args)
+	case "board":
+		if s.game == nil {
+			return ErrNoGame
+		}
+		return nil
+	case "quit":
+		s.quit = true
+		return nil
+	default:
+		return fmt.Errorf("%w %q", ErrUnknownCommand, args[0])
+	}
+}
+
+func (s *Session) newGame(args []string) error {
+	d := s.difficulty
+	var opts []game.Option
+
+	if len(args) > 0 && !strings.EqualFold(args[0], "seed") {
+		parsed, err := game.ParseDifficulty(args[0])
+		if err != nil {
+			return err
+		}
+		d = parsed
+		args = args[1:]
+	}
+	if err := d.CheckSize(); err != nil {
+		return err
+	}
+
+	if len(args) > 0 {
+		if len(args) != 2 || !strings.EqualFold(args[0], "seed") {
+			return errors.New("usage: new [difficulty] [seed n]")
+		}
+		seed, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid seed %q", args[1])
+		}
+		opts = append(opts, game.WithSeed(seed))
+	}
+
+	g, err := game.NewWithDifficulty(d, opts...)
+	if err != nil {
+		return err
+	}
+	s.game = g
+
+	return nil
+}
+
+func (s *Session) move(args []string) error {
+	if s.game == nil {
+		return ErrNoGame
+	}
+	if len(args) != 3 {
+		return fmt.Errorf("usage: %s x y", args[0])
+	}
+
+	x, errX := strconv.Atoi(args[1])
+	y, errY := strconv.Atoi(args[2])
+	if errX != nil || errY != nil {
+		return fmt.Errorf("invalid coordinates %s %s", args[1], args[2])
+	}
+	if x < 0 || y < 0 || x >= s.game.GetGridWidth() || y >= s.game.GetGridHeight() {
+		return game.ErrOutOfBounds
+	}
+
+	var err error
+	switch strings.ToLower(args[0]) {
+	case "reveal":
+		_, err = s.game.Reveal(x, y)
+	case "flag":
+		s.game.ToggleFlag(x, y)
+	case "chord":
+		_, err = s.game.Chord(x, y)
+	}
+
+	return err
+}
+
+func (s *Session) response() Response {
+	res := Response{OK: true}
+	if s.game == nil {
+		return res
+	}
+
+	snapshot := s.game.Snapshot()
+	res.Board = &Board{
+		Width:  snapshot.Width,
+		Height: snapshot.Height,
+		Mines:  snapshot.MineCount,
+		Flags:  snapshot.FlagCount,
+		State:  stateName(snapshot.State),
+		Cells:  cells(snapshot),
+	}
+
+	if snapshot.State != game.StatePlaying {
+		stats := s.game.Stats()
+		res.Result = &Result{
+			Won:              snapshot.State == game.StateWon,
+			Seed:             s.game.Seed(),
+			TimeMillis:       stats.Elapsed.Milliseconds(),
+			Clicks:           stats.Clicks,
+			ThreeBV:          stats.ThreeBV,
+			ThreeBVPerSecond: stats.ThreeBVPerSecond(),
+		}
+	}
+
+	return res
+}
+
+func stateName(s game.State) string {
+	switch s {
+	case game.StateWon:
+		return "won"
+	case game.StateLost:
+		return "lost"
+	default:
+		return "playing"
+	}
+}
+
+func cells(s game.Snapshot) []string {
+	rows := make([]string, s.Height)
+	for y, row := range s.Cells {
+		b := &strings.Builder{}
+		for _, c := range row {
+			b.WriteByte(cellChar(c))
+		}
+		rows[y] = b.String()
+	}
+	return rows
+}
+
+func cellChar(c game.CellView) byte {
+	switch {
+	case c.Exploded:
+		return 'X'
+	case c.WrongFlag:
+		return '!'
+	case c.State == game.CellStateMine:
+		return '*'
+	case c.State == game.CellStateFlagged:
+		return 'F'
+	case c.State == game.CellStateRevealed:
+		return byte('0' + c.AdjacentMines)
+	default:
+		return '#'
+	}
+}
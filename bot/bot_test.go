@@ -0,0 +1,116 @@
+package bot_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"github.com/jboewer/minesshweeper/bot"
+	"github.com/jboewer/minesshweeper/game"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestSession(t *testing.T) {
+	t.Run("Needs a game", func(t *testing.T) {
+		s := bot.NewSession(game.Beginner)
+
+		res := s.Handle("reveal 0 0")
+
+		assert.False(t, res.OK)
+		assert.Equal(t, bot.ErrNoGame.Error(), res.Error)
+		assert.Nil(t, res.Board)
+	})
+	t.Run("New game", func(t *testing.T) {
+		s := bot.NewSession(game.Beginner)
+
+		res := s.Handle("new seed 42")
+
+		assert.True(t, res.OK)
+		assert.Equal(t, 9, res.Board.Width)
+		assert.Equal(t, 10, res.Board.Mines)
+		assert.Equal(t, "playing", res.Board.State)
+		assert.Equal(t, strings.Repeat("#", 9), res.Board.Cells[0])
+		assert.Nil(t, res.Result)
+
+		res = s.Handle("new 20x10/30")
+		assert.True(t, res.OK)
+		assert.Equal(t, 20, res.Board.Width)
+		assert.Equal(t, 30, res.Board.Mines)
+	})
+	t.Run("Same seed, same board", func(t *testing.T) {
+		a, b := bot.NewSession(game.Beginner), bot.NewSession(game.Beginner)
+		a.Handle("new expert seed 7")
+		b.Handle("new expert seed 7")
+
+		assert.Equal(t, a.Handle("reveal 3 3"), b.Handle("reveal 3 3"))
+	})
+	t.Run("Mines stay hidden until the game is lost", func(t *testing.T) {
+		s := bot.NewSession(game.Beginner)
+		s.Handle("new 5x5/20 seed 1")
+
+		var res bot.Response
+		for i := 0; i < 25; i++ {
+			res = s.Handle("reveal " + string(rune('0'+i%5)) + " " + string(rune('0'+i/5)))
+			if res.Board.State != "playing" {
+				break
+			}
+			assert.NotContains(t, strings.Join(res.Board.Cells, ""), "*")
+		}
+
+		assert.Equal(t, "lost", res.Board.State)
+		assert.Contains(t, strings.Join(res.Board.Cells, ""), "X")
+		assert.Contains(t, strings.Join(res.Board.Cells, ""), "*")
+		assert.False(t, res.Result.Won)
+		assert.Equal(t, int64(1), res.Result.Seed)
+	})
+	t.Run("Flag", func(t *testing.T) {
+		s := bot.NewSession(game.Beginner)
+		s.Handle("new")
+
+		res := s.Handle("flag 2 1")
+
+		assert.True(t, res.OK)
+		assert.Equal(t, 1, res.Board.Flags)
+		assert.Equal(t, "##F######", res.Board.Cells[1])
+	})
+	t.Run("Invalid commands", func(t *testing.T) {
+		s := bot.NewSession(game.Beginner)
+		s.Handle("new")
+
+		for _, line := range []string{
+			"dance",
+			"reveal 1",
+			"reveal a b",
+			"reveal 9 0",
+			"chord -1 0",
+			"new impossible",
+			"new 100000x100000/1",
+			"new seed",
+			"new beginner seed x",
+		} {
+			res := s.Handle(line)
+			assert.False(t, res.OK, line)
+			assert.NotEmpty(t, res.Error, line)
+			assert.NotNil(t, res.Board, line)
+		}
+	})
+}
+
+func TestServe(t *testing.T) {
+	in := strings.NewReader("new 2x1/1 seed 3\n\nboard\nquit\nboard\n")
+	out := &strings.Builder{}
+
+	assert.NoError(t, bot.Serve(in, out, game.Beginner))
+
+	var responses []bot.Response
+	scanner := bufio.NewScanner(strings.NewReader(out.String()))
+	for scanner.Scan() {
+		var res bot.Response
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), &res))
+		responses = append(responses, res)
+	}
+
+	assert.Len(t, responses, 3)
+	assert.Equal(t, []string{"##"}, responses[0].Board.Cells)
+	assert.True(t, responses[2].OK)
+}
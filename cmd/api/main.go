@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"github.com/charmbracelet/log"
+	"github.com/jboewer/minesshweeper/api"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+func main() {
+	address := flag.String("address", "localhost:8080", "address to listen on")
+	ttl := flag.Duration("ttl", time.Hour, "remove games that haven't been played for this long")
+	flag.Parse()
+
+	store := api.NewStore(*ttl)
+	s := &http.Server{
+		Addr:              *address,
+		Handler:           api.NewHandler(store),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ln, err := net.Listen("tcp", *address)
+	if err != nil {
+		log.Fatal("Could not listen", "address", *address, "error", err)
+	}
+
+	done := make(chan os.Signal, 1)
+	signal.Notify(done, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
+	log.Info("Starting HTTP server", "address", *address)
+	go func() {
+		if err := s.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal("Server stopped", "error", err)
+		}
+	}()
+
+	go func() {
+		for range time.Tick(time.Minute) {
+			if n := store.Expire(); n > 0 {
+				log.Info("Removed expired games", "count", n)
+			}
+		}
+	}()
+
+	<-done
+	log.Info("Stopping HTTP server")
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer func() { cancel() }()
+	if err := s.Shutdown(ctx); err != nil {
+		log.Error("Could not stop server", "error", err)
+	}
+}
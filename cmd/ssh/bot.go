@@ -0,0 +1,31 @@
+package main
+
+import (
+	"github.com/charmbracelet/log"
+	"github.com/charmbracelet/ssh"
+	"github.com/charmbracelet/wish"
+	"github.com/jboewer/minesshweeper/bot"
+)
+
+// botMiddleware hands sessions running the bot command over to the bot
+// protocol. Bots don't need a terminal, so it has to run before
+// activeterm.Middleware.
+func botMiddleware() wish.Middleware {
+	return func(next ssh.Handler) ssh.Handler {
+		return func(s ssh.Session) {
+			cmd := s.Command()
+			if len(cmd) == 0 || cmd[0] != "bot" {
+				next(s)
+				return
+			}
+			if len(cmd) > 1 {
+				fatal(s, "bot takes no arguments, send commands on its input instead")
+				return
+			}
+
+			if err := bot.Serve(s, s, services.Difficulty); err != nil {
+				log.Error("Bot session failed", "error", err)
+			}
+		}
+	}
+}
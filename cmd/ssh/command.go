@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+	"github.com/jboewer/minesshweeper/game"
+	"strconv"
+	"strings"
+)
+
+const usage = `Usage: ssh -t <host> -p <port> [command]
+
+Commands:
+  beginner | intermediate | expert   play alone on a preset board
+  custom <width> <height> <mines>    play alone on a custom board
+  seed <n>                           play a board laid out from seed n
+  daily                              open today's daily challenge
+  theme <name>                       draw the board with a theme: classic,
+                                     dark, high-contrast or colorblind
+  keys <name>                        play with a key map: default, vim,
+                                     wasd, arrows or azerty
+  bot                                play through the line-based bot
+                                     protocol, without -t
+
+A board and a seed can be combined, as in "expert seed 12345", and a
+theme and keys go with anything, as in "daily theme colorblind keys vim".
+Without a command you start in the lobby.`
+
+// command is what the player asked for on the SSH command line.
+type command struct {
+	// difficulty is set if the player picked a board.
+	difficulty *game.Difficulty
+	// seed is set if the player asked for a specific board layout.
+	seed  *int64
+	daily bool
+	// theme is the name of the theme the player asked for, if any.
+	theme string
+	// keys is the name of the key map the player asked for, if any.
+	keys string
+}
+
+// play reports whether the command starts a game right away.
+func (c command) play() bool {
+	return c.difficulty != nil || c.seed != nil
+}
+
+func parseCommand(args []string) (command, error) {
+	var c command
+
+	for len(args) > 0 {
+		name := strings.ToLower(args[0])
+		switch {
+		case name == "custom" && c.difficulty == nil:
+			if len(args) < 4 {
+				return command{}, fmt.Errorf("custom needs a width, a height and a number of mines")
+			}
+
+			var size [3]int
+			for i, arg := range args[1:4] {
+				n, err := strconv.Atoi(arg)
+				if err != nil {
+					return command{}, fmt.Errorf("custom: %q is not a number", arg)
+				}
+				size[i] = n
+			}
+
+			d := game.DifficultyOf(size[0], size[1], size[2])
+			if err := d.CheckSize(); err != nil {
+				return command{}, fmt.Errorf("custom: %w", err)
+			}
+			if err := d.Validate(); err != nil {
+				return command{}, fmt.Errorf("custom: a %dx%d board can't hold %d mines", d.Width, d.Height, d.Mines)
+			}
+			c.difficulty = &d
+			args = args[4:]
+		case name == "seed" && c.seed == nil:
+			if len(args) < 2 {
+				return command{}, fmt.Errorf("seed needs a number")
+			}
+
+			seed, err := strconv.ParseInt(args[1], 10, 64)
+			if err != nil {
+				return command{}, fmt.Errorf("seed: %q is not a number", args[1])
+			}
+			c.seed = &seed
+			args = args[2:]
+		case name == "daily" && !c.daily:
+			c.daily = true
+			args = args[1:]
+		case name == "theme" && c.theme == "":
+			if len(args) < 2 {
+				return command{}, fmt.Errorf("theme needs a name")
+			}
+			c.theme = args[1]
+			args = args[2:]
+		case name == "keys" && c.keys == "":
+			if len(args) < 2 {
+				return command{}, fmt.Errorf("keys needs a name")
+			}
+			c.keys = args[1]
+			args = args[2:]
+		case c.difficulty == nil && isPreset(name):
+			d, _ := game.ParseDifficulty(name)
+			c.difficulty = &d
+			args = args[1:]
+		default:
+			return command{}, fmt.Errorf("unexpected %q", args[0])
+		}
+	}
+
+	if c.daily && c.play() {
+		return command{}, fmt.Errorf("daily has its own board")
+	}
+
+	return c, nil
+}
+
+func isPreset(name string) bool {
+	for _, d := range game.Difficulties {
+		if strings.EqualFold(d.Name, name) {
+			return true
+		}
+	}
+	return false
+}
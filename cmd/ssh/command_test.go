@@ -0,0 +1,97 @@
+package main
+
+import (
+	"github.com/jboewer/minesshweeper/game"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseCommand(t *testing.T) {
+	t.Run("Lobby", func(t *testing.T) {
+		c, err := parseCommand(nil)
+
+		assert.NoError(t, err)
+		assert.False(t, c.play())
+		assert.False(t, c.daily)
+	})
+	t.Run("Preset", func(t *testing.T) {
+		c, err := parseCommand([]string{"Expert"})
+
+		assert.NoError(t, err)
+		assert.True(t, c.play())
+		assert.Equal(t, game.Expert, *c.difficulty)
+		assert.Nil(t, c.seed)
+	})
+	t.Run("Custom", func(t *testing.T) {
+		c, err := parseCommand([]string{"custom", "40", "20", "150"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, game.DifficultyOf(40, 20, 150), *c.difficulty)
+	})
+	t.Run("Seed", func(t *testing.T) {
+		c, err := parseCommand([]string{"seed", "12345"})
+
+		assert.NoError(t, err)
+		assert.True(t, c.play())
+		assert.Nil(t, c.difficulty)
+		assert.Equal(t, int64(12345), *c.seed)
+	})
+	t.Run("Board and seed", func(t *testing.T) {
+		c, err := parseCommand([]string{"beginner", "seed", "-7"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, game.Beginner, *c.difficulty)
+		assert.Equal(t, int64(-7), *c.seed)
+	})
+	t.Run("Daily", func(t *testing.T) {
+		c, err := parseCommand([]string{"daily"})
+
+		assert.NoError(t, err)
+		assert.True(t, c.daily)
+		assert.False(t, c.play())
+	})
+	t.Run("Theme", func(t *testing.T) {
+		c, err := parseCommand([]string{"expert", "theme", "dark"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, game.Expert, *c.difficulty)
+		assert.Equal(t, "dark", c.theme)
+
+		c, err = parseCommand([]string{"daily", "theme", "colorblind"})
+
+		assert.NoError(t, err)
+		assert.True(t, c.daily)
+		assert.Equal(t, "colorblind", c.theme)
+	})
+	t.Run("Keys", func(t *testing.T) {
+		c, err := parseCommand([]string{"keys", "vim", "theme", "dark"})
+
+		assert.NoError(t, err)
+		assert.False(t, c.play())
+		assert.Equal(t, "vim", c.keys)
+		assert.Equal(t, "dark", c.theme)
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		for _, args := range [][]string{
+			{"hard"},
+			{"custom", "10", "10"},
+			{"custom", "10", "ten", "10"},
+			{"custom", "3", "3", "9"},
+			{"custom", "100000", "100000", "1"},
+			{"custom", "101", "10", "10"},
+			{"seed"},
+			{"seed", "abc"},
+			{"expert", "beginner"},
+			{"daily", "expert"},
+			{"seed", "1", "daily"},
+			{"theme"},
+			{"theme", "dark", "theme", "classic"},
+			{"keys"},
+			{"keys", "vim", "keys", "wasd"},
+			{"expert", "seed", "1", "seed", "2"},
+		} {
+			_, err := parseCommand(args)
+			assert.Error(t, err, args)
+		}
+	})
+}
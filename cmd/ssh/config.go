@@ -0,0 +1,181 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/jboewer/minesshweeper/game"
+	"gopkg.in/yaml.v3"
+	"io"
+	"os"
+	"strconv"
+	"time"
+)
+
+const envPrefix = "MINESSHWEEPER_"
+
+// Config is how the server is set up. Settings are read from the config
+// file first, then from the environment, then from the command line, with
+// later sources taking precedence.
+type Config struct {
+	Address     string `yaml:"address"`
+	HostKeyPath string `yaml:"host_key"`
+	// Difficulty is the board for players without a preference: the name
+	// of a preset or a custom board such as 10x10/10.
+	Difficulty string `yaml:"difficulty"`
+	// MaxSessions limits the number of players connected at once. 0 means
+	// no limit.
+	MaxSessions int `yaml:"max_sessions"`
+	// IdleTimeout disconnects sessions without any traffic. 0 means no
+	// timeout.
+	IdleTimeout time.Duration `yaml:"idle_timeout"`
+	// ThemesPath is a YAML file with custom themes players can pick on top
+	// of the built-in ones.
+	ThemesPath string `yaml:"themes"`
+	// KeysPath is a YAML file with custom key maps players can pick on top
+	// of the built-in presets.
+	KeysPath string `yaml:"keys"`
+}
+
+func defaultConfig() Config {
+	return Config{
+		Address:     "0.0.0.0:23234",
+		HostKeyPath: ".ssh/id_ed25519",
+		Difficulty:  "10x10/10",
+	}
+}
+
+// loadConfig reads the configuration from the command line arguments, the
+// environment and the config file named by -config or
+// MINESSHWEEPER_CONFIG.
+func loadConfig(args []string, getenv func(string) string) (Config, error) {
+	var flags Config
+	var path string
+
+	fs := flag.NewFlagSet("minesshweeper", flag.ContinueOnError)
+	fs.StringVar(&path, "config", "", "path of a YAML config file")
+	fs.StringVar(&flags.Address, "address", "", "address to listen on")
+	fs.StringVar(&flags.HostKeyPath, "host-key", "", "path of the SSH host key, created if missing")
+	fs.StringVar(&flags.Difficulty, "difficulty", "", "default board: beginner, intermediate, expert or WxH/M")
+	fs.IntVar(&flags.MaxSessions, "max-sessions", 0, "maximum number of concurrent sessions, 0 for no limit")
+	fs.DurationVar(&flags.IdleTimeout, "idle-timeout", 0, "disconnect idle sessions after this long, 0 for never")
+	fs.StringVar(&flags.ThemesPath, "themes", "", "path of a YAML file with custom themes")
+	fs.StringVar(&flags.KeysPath, "keys", "", "path of a YAML file with custom key maps")
+	if err := fs.Parse(args); err != nil {
+		return Config{}, err
+	}
+
+	cfg := defaultConfig()
+
+	if path == "" {
+		path = getenv(envPrefix + "CONFIG")
+	}
+	if path != "" {
+		if err := cfg.readFile(path); err != nil {
+			return Config{}, err
+		}
+	}
+
+	if err := cfg.readEnv(getenv); err != nil {
+		return Config{}, err
+	}
+
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "address":
+			cfg.Address = flags.Address
+		case "host-key":
+			cfg.HostKeyPath = flags.HostKeyPath
+		case "difficulty":
+			cfg.Difficulty = flags.Difficulty
+		case "max-sessions":
+			cfg.MaxSessions = flags.MaxSessions
+		case "idle-timeout":
+			cfg.IdleTimeout = flags.IdleTimeout
+		case "themes":
+			cfg.ThemesPath = flags.ThemesPath
+		case "keys":
+			cfg.KeysPath = flags.KeysPath
+		}
+	})
+
+	return cfg, cfg.validate()
+}
+
+func (cfg *Config) readFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("could not open config file: %w", err)
+	}
+	defer f.Close()
+
+	dec := yaml.NewDecoder(f)
+	dec.KnownFields(true)
+	if err := dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("could not read config file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+func (cfg *Config) readEnv(getenv func(string) string) error {
+	if v := getenv(envPrefix + "ADDRESS"); v != "" {
+		cfg.Address = v
+	}
+	if v := getenv(envPrefix + "HOST_KEY"); v != "" {
+		cfg.HostKeyPath = v
+	}
+	if v := getenv(envPrefix + "DIFFICULTY"); v != "" {
+		cfg.Difficulty = v
+	}
+	if v := getenv(envPrefix + "MAX_SESSIONS"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("invalid %sMAX_SESSIONS: %w", envPrefix, err)
+		}
+		cfg.MaxSessions = n
+	}
+	if v := getenv(envPrefix + "IDLE_TIMEOUT"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("invalid %sIDLE_TIMEOUT: %w", envPrefix, err)
+		}
+		cfg.IdleTimeout = d
+	}
+	if v := getenv(envPrefix + "THEMES"); v != "" {
+		cfg.ThemesPath = v
+	}
+	if v := getenv(envPrefix + "KEYS"); v != "" {
+		cfg.KeysPath = v
+	}
+
+	return nil
+}
+
+func (cfg Config) validate() error {
+	if cfg.Address == "" {
+		return errors.New("address must not be empty")
+	}
+	if cfg.HostKeyPath == "" {
+		return errors.New("host key path must not be empty")
+	}
+	if _, err := cfg.difficulty(); err != nil {
+		return err
+	}
+	if cfg.MaxSessions < 0 {
+		return errors.New("max sessions must not be negative")
+	}
+	if cfg.IdleTimeout < 0 {
+		return errors.New("idle timeout must not be negative")
+	}
+
+	return nil
+}
+
+func (cfg Config) difficulty() (game.Difficulty, error) {
+	d, err := game.ParseDifficulty(cfg.Difficulty)
+	if err != nil {
+		return game.Difficulty{}, fmt.Errorf("invalid difficulty: %w", err)
+	}
+	return d, nil
+}
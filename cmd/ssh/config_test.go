@@ -0,0 +1,73 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func env(vars map[string]string) func(string) string {
+	return func(key string) string {
+		return vars[key]
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	err := os.WriteFile(path, []byte("address: 127.0.0.1:2222\ndifficulty: expert\nmax_sessions: 5\nidle_timeout: 10m\n"), 0o644)
+	assert.NoError(t, err)
+
+	t.Run("Defaults", func(t *testing.T) {
+		cfg, err := loadConfig(nil, env(nil))
+
+		assert.NoError(t, err)
+		assert.Equal(t, defaultConfig(), cfg)
+	})
+	t.Run("File", func(t *testing.T) {
+		cfg, err := loadConfig([]string{"-config", path}, env(nil))
+
+		assert.NoError(t, err)
+		assert.Equal(t, Config{
+			Address:     "127.0.0.1:2222",
+			HostKeyPath: ".ssh/id_ed25519",
+			Difficulty:  "expert",
+			MaxSessions: 5,
+			IdleTimeout: 10 * time.Minute,
+		}, cfg)
+	})
+	t.Run("Precedence", func(t *testing.T) {
+		cfg, err := loadConfig([]string{"-max-sessions", "7"}, env(map[string]string{
+			"MINESSHWEEPER_CONFIG":       path,
+			"MINESSHWEEPER_MAX_SESSIONS": "6",
+			"MINESSHWEEPER_HOST_KEY":     "/etc/minesshweeper/key",
+			"MINESSHWEEPER_THEMES":       "themes.yaml",
+			"MINESSHWEEPER_KEYS":         "keys.yaml",
+		}))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "127.0.0.1:2222", cfg.Address)
+		assert.Equal(t, "/etc/minesshweeper/key", cfg.HostKeyPath)
+		assert.Equal(t, 7, cfg.MaxSessions)
+		assert.Equal(t, "themes.yaml", cfg.ThemesPath)
+		assert.Equal(t, "keys.yaml", cfg.KeysPath)
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := loadConfig([]string{"-difficulty", "impossible"}, env(nil))
+		assert.Error(t, err)
+
+		_, err = loadConfig(nil, env(map[string]string{"MINESSHWEEPER_IDLE_TIMEOUT": "soon"}))
+		assert.Error(t, err)
+
+		_, err = loadConfig([]string{"-config", filepath.Join(t.TempDir(), "missing.yaml")}, env(nil))
+		assert.Error(t, err)
+	})
+	t.Run("Unknown setting", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		assert.NoError(t, os.WriteFile(path, []byte("port: 22\n"), 0o644))
+
+		_, err := loadConfig([]string{"-config", path}, env(nil))
+		assert.Error(t, err)
+	})
+}
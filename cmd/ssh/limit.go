@@ -0,0 +1,42 @@
+package main
+
+import (
+	"github.com/charmbracelet/ssh"
+	"github.com/charmbracelet/wish"
+	"sync"
+)
+
+// limitSessions turns new sessions away while max sessions are connected. A
+// max of 0 lets everyone in.
+func limitSessions(max int) wish.Middleware {
+	var mu sync.Mutex
+	active := 0
+
+	return func(next ssh.Handler) ssh.Handler {
+		return func(s ssh.Session) {
+			if max == 0 {
+				next(s)
+				return
+			}
+
+			mu.Lock()
+			full := active >= max
+			if !full {
+				active++
+			}
+			mu.Unlock()
+
+			if full {
+				wish.Fatalln(s, "The server is full, please try again later.")
+				return
+			}
+
+			defer func() {
+				mu.Lock()
+				active--
+				mu.Unlock()
+			}()
+			next(s)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"errors"
+	"flag"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/log"
 	"github.com/charmbracelet/ssh"
@@ -10,41 +11,117 @@ import (
 	"github.com/charmbracelet/wish/activeterm"
 	"github.com/charmbracelet/wish/bubbletea"
 	"github.com/charmbracelet/wish/logging"
-	"github.com/jboewer/minesshweeper/game"
+	"github.com/jboewer/minesshweeper/daily"
+	"github.com/jboewer/minesshweeper/leaderboard"
+	"github.com/jboewer/minesshweeper/profile"
+	"github.com/jboewer/minesshweeper/room"
+	"github.com/jboewer/minesshweeper/spectate"
 	"github.com/jboewer/minesshweeper/tui"
+	"github.com/jboewer/minesshweeper/versus"
+	gossh "golang.org/x/crypto/ssh"
 	"net"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 )
 
 const (
-	host = "0.0.0.0"
-	port = "23234"
+	resultsPath  = "data/results.jsonl"
+	profilesPath = "data/profiles.json"
 )
 
+// services holds the state that is shared between sessions.
+var services = &tui.Services{
+	Rooms:     room.NewRegistry(),
+	Matches:   versus.NewRegistry(),
+	Sessions:  spectate.NewRegistry(),
+	LinkCodes: profile.NewLinkCodes(),
+}
+
 func main() {
+	cfg, err := loadConfig(os.Args[1:], os.Getenv)
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
+	}
+	if err != nil {
+		log.Fatal("Invalid configuration", "error", err)
+	}
+
+	d, _ := cfg.difficulty()
+	services.Difficulty = d
+	services.Race = versus.Settings{
+		Width:  d.Width,
+		Height: d.Height,
+		Mines:  d.Mines,
+	}
+
+	results, err := leaderboard.OpenFileStore(resultsPath)
+	if err != nil {
+		log.Fatal("Could not open results", "error", err)
+	}
+	services.Results = results
+	services.Attempts = daily.NewAttempts(results)
+
+	profiles, err := profile.OpenFileStore(profilesPath)
+	if err != nil {
+		log.Fatal("Could not open profiles", "error", err)
+	}
+	services.Profiles = profiles
+
+	services.Themes = tui.Themes
+	if cfg.ThemesPath != "" {
+		themes, err := tui.LoadThemes(cfg.ThemesPath)
+		if err != nil {
+			log.Fatal("Could not load themes", "error", err)
+		}
+		services.Themes = append(services.Themes, themes...)
+	}
+
+	services.KeyMaps = tui.KeyMaps
+	if cfg.KeysPath != "" {
+		keyMaps, err := tui.LoadKeyMaps(cfg.KeysPath)
+		if err != nil {
+			log.Fatal("Could not load key maps", "error", err)
+		}
+		services.KeyMaps = append(services.KeyMaps, keyMaps...)
+	}
+
 	s, err := wish.NewServer(
-		wish.WithAddress(net.JoinHostPort(host, port)),
-		wish.WithHostKeyPath(".ssh/id_ed25519"),
+		wish.WithAddress(cfg.Address),
+		wish.WithHostKeyPath(cfg.HostKeyPath),
+		wish.WithIdleTimeout(cfg.IdleTimeout),
+		// Any key is welcome; it is only used to recognise returning players.
+		wish.WithPublicKeyAuth(func(ssh.Context, ssh.PublicKey) bool {
+			return true
+		}),
+		wish.WithKeyboardInteractiveAuth(func(ssh.Context, gossh.KeyboardInteractiveChallenge) bool {
+			return true
+		}),
 		wish.WithMiddleware(
 			bubbletea.Middleware(teaHandler),
 			activeterm.Middleware(), // Bubble Tea apps usually require a PTY.
+			botMiddleware(),
+			limitSessions(cfg.MaxSessions),
 			logging.Middleware(),
 		),
 	)
 	if err != nil {
-		log.Error("Could not start server", "error", err)
+		log.Fatal("Could not create server", "error", err)
+	}
+
+	ln, err := net.Listen("tcp", cfg.Address)
+	if err != nil {
+		log.Fatal("Could not listen", "address", cfg.Address, "error", err)
 	}
 
 	done := make(chan os.Signal, 1)
 	signal.Notify(done, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
-	log.Info("Starting SSH server", "host", host, "port", port)
+	log.Info("Starting SSH server", "address", cfg.Address, "difficulty", d.Name)
 	go func() {
-		if err = s.ListenAndServe(); err != nil && !errors.Is(err, ssh.ErrServerClosed) {
-			log.Error("Could not start server", "error", err)
-			done <- nil
+		if err := s.Serve(ln); err != nil && !errors.Is(err, ssh.ErrServerClosed) {
+			log.Fatal("Server stopped", "error", err)
 		}
 	}()
 
@@ -62,13 +139,117 @@ func main() {
 // pass it to the new model. You can also return tea.ProgramOptions (such as
 // tea.WithAltScreen) on a session by session basis.
 func teaHandler(s ssh.Session) (tea.Model, []tea.ProgramOption) {
-	g, err := game.New(10, 10)
+	cmd, err := parseCommand(s.Command())
 	if err != nil {
-		log.Error("Could not create game", "error", err)
+		fatal(s, "Error: "+err.Error()+"\n\n"+usage)
 		return nil, nil
 	}
 
-	m := tui.NewGameModel(g)
-	g.PlaceRandomMines(10)
-	return m, []tea.ProgramOption{tea.WithAltScreen()}
+	playerID := s.Context().SessionID()
+	go func() {
+		<-s.Context().Done()
+		services.Rooms.Leave(playerID)
+		services.Matches.Leave(playerID)
+		services.Sessions.Remove(playerID)
+		services.Sessions.Unwatch(playerID)
+	}()
+
+	opts := []tea.ProgramOption{tea.WithAltScreen(), tea.WithMouseAllMotion()}
+	// The renderer picks colours from the client's TERM, and drops them
+	// when the client sends NO_COLOR.
+	settings := tui.DefaultSettings(bubbletea.MakeRenderer(s))
+	player := tui.Player{
+		SessionID: playerID,
+		KeyID:     keyID(s),
+		Address:   address(s),
+		Name:      s.User(),
+	}
+	if player.KeyID != "" {
+		p, err := services.Profiles.ByKey(player.KeyID)
+		switch {
+		case errors.Is(err, profile.ErrNotFound):
+			// Players who start straight into a game can pick a nickname
+			// next time.
+			if !cmd.play() {
+				return tui.NewProfileSetupModel(services, player, settings), opts
+			}
+		case err != nil:
+			log.Error("Could not load profile", "error", err)
+			return nil, nil
+		default:
+			player.ProfileID = p.ID
+			player.Name = p.Name
+			settings.Theme = services.Theme(p.Theme)
+			settings.Keys = services.KeyMap(p.KeyBindings)
+		}
+	}
+
+	if cmd.theme != "" {
+		t, ok := tui.FindTheme(services.Themes, cmd.theme)
+		if !ok {
+			fatal(s, "Error: unknown theme "+cmd.theme)
+			return nil, nil
+		}
+		settings.Theme = t
+	}
+	if cmd.keys != "" {
+		k, ok := tui.FindKeyMap(services.KeyMaps, cmd.keys)
+		if !ok {
+			fatal(s, "Error: unknown keys "+cmd.keys)
+			return nil, nil
+		}
+		settings.Keys = k
+	}
+
+	lobby := tui.NewLobbyModel(services, player, settings)
+	switch {
+	case cmd.daily:
+		return lobby.Daily(), opts
+	case cmd.play():
+		d := lobby.Difficulty()
+		if cmd.difficulty != nil {
+			d = *cmd.difficulty
+		}
+
+		var m tea.Model
+		if cmd.seed != nil {
+			m, err = lobby.PlaySeed(d, *cmd.seed)
+		} else {
+			m, err = lobby.PlayAlone(d)
+		}
+		if err != nil {
+			fatal(s, "Could not start the game: "+err.Error())
+			return nil, nil
+		}
+		return m, opts
+	}
+
+	return lobby, opts
+}
+
+// fatal prints msg on the session and ends it. Terminals in raw mode need
+// explicit carriage returns.
+func fatal(s ssh.Session, msg string) {
+	if _, _, ok := s.Pty(); ok {
+		msg = strings.ReplaceAll(msg, "\n", "\r\n")
+	}
+	wish.Fatalln(s, msg)
+}
+
+// address is the host the session comes from, without its port.
+func address(s ssh.Session) string {
+	host, _, err := net.SplitHostPort(s.RemoteAddr().String())
+	if err != nil {
+		return s.RemoteAddr().String()
+	}
+	return host
+}
+
+// keyID identifies players by the fingerprint of their public key. Players
+// who connect without a key stay anonymous.
+func keyID(s ssh.Session) string {
+	if s.PublicKey() == nil {
+		return ""
+	}
+	return gossh.FingerprintSHA256(s.PublicKey())
 }
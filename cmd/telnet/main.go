@@ -0,0 +1,116 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/log"
+	"github.com/jboewer/minesshweeper/game"
+	"github.com/jboewer/minesshweeper/telnet"
+	"github.com/jboewer/minesshweeper/tui"
+	"github.com/muesli/termenv"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// negotiationTimeout is how long to wait for the window size.
+const negotiationTimeout = time.Second
+
+func main() {
+	address := flag.String("address", "localhost:2323", "address to listen on")
+	difficulty := flag.String("difficulty", "10x10/10", "board: beginner, intermediate, expert or WxH/M")
+	flag.Parse()
+
+	d, err := game.ParseDifficulty(*difficulty)
+	if err != nil {
+		log.Fatal("Invalid difficulty", "error", err)
+	}
+
+	ln, err := net.Listen("tcp", *address)
+	if err != nil {
+		log.Fatal("Could not listen", "address", *address, "error", err)
+	}
+
+	log.Info("Starting telnet server", "address", *address)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+			if err != nil {
+				log.Error("Could not accept connection", "error", err)
+				continue
+			}
+			go serve(conn, d)
+		}
+	}()
+
+	done := make(chan os.Signal, 1)
+	signal.Notify(done, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
+	<-done
+
+	log.Info("Stopping telnet server")
+	ln.Close()
+}
+
+// serve plays a game with a single telnet client.
+func serve(conn net.Conn, d game.Difficulty) {
+	defer conn.Close()
+	log.Info("Client connected", "remote", conn.RemoteAddr())
+
+	tc := telnet.NewConn(conn)
+	resized := make(chan struct{}, 1)
+	tc.OnResize(func(int, int) {
+		select {
+		case resized <- struct{}{}:
+		default:
+		}
+	})
+
+	if err := tc.Negotiate(); err != nil {
+		return
+	}
+
+	g, err := game.NewWithDifficulty(d)
+	if err != nil {
+		log.Error("Could not start game", "error", err)
+		return
+	}
+
+	// Telnet can't tell what the terminal supports, so assume 256 colours.
+	renderer := lipgloss.NewRenderer(tc)
+	renderer.SetColorProfile(termenv.ANSI256)
+
+	p := tea.NewProgram(
+		tui.NewGameModel(g, tui.DefaultSettings(renderer)),
+		tea.WithInput(tc),
+		tea.WithOutput(tc),
+		tea.WithAltScreen(),
+		tea.WithMouseAllMotion(),
+	)
+
+	// The program reads the client's replies, so it has to run first.
+	go func() {
+		select {
+		case <-resized:
+		case <-time.After(negotiationTimeout):
+		}
+
+		tc.OnResize(func(width, height int) {
+			p.Send(tea.WindowSizeMsg{Width: width, Height: height})
+		})
+		if width, height := tc.Size(); width > 0 && height > 0 {
+			p.Send(tea.WindowSizeMsg{Width: width, Height: height})
+		}
+	}()
+
+	if _, err := p.Run(); err != nil {
+		log.Error("Program failed", "error", err)
+	}
+	log.Info("Client disconnected", "remote", conn.RemoteAddr())
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"github.com/charmbracelet/log"
+	"github.com/jboewer/minesshweeper/game"
+	"github.com/jboewer/minesshweeper/leaderboard"
+	"github.com/jboewer/minesshweeper/web"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+func main() {
+	address := flag.String("address", "localhost:8081", "address to listen on")
+	resultsPath := flag.String("results", "data/results.jsonl", "results file, shared with the SSH server")
+	difficulty := flag.String("difficulty", "beginner", "board for new visitors: beginner, intermediate, expert or WxH/M")
+	flag.Parse()
+
+	d, err := game.ParseDifficulty(*difficulty)
+	if err != nil {
+		log.Fatal("Invalid difficulty", "error", err)
+	}
+
+	results, err := leaderboard.OpenFileStore(*resultsPath)
+	if err != nil {
+		log.Fatal("Could not open results", "error", err)
+	}
+
+	s := &http.Server{
+		Addr:              *address,
+		Handler:           web.NewHandler(results, d),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ln, err := net.Listen("tcp", *address)
+	if err != nil {
+		log.Fatal("Could not listen", "address", *address, "error", err)
+	}
+
+	done := make(chan os.Signal, 1)
+	signal.Notify(done, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
+	log.Info("Starting web server", "address", *address)
+	go func() {
+		if err := s.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal("Server stopped", "error", err)
+		}
+	}()
+
+	<-done
+	log.Info("Stopping web server")
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer func() { cancel() }()
+	if err := s.Shutdown(ctx); err != nil {
+		log.Error("Could not stop server", "error", err)
+	}
+}
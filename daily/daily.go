@@ -0,0 +1,200 @@
+package daily
+
+import (
+	"errors"
+	"github.com/jboewer/minesshweeper/game"
+	"github.com/jboewer/minesshweeper/leaderboard"
+	"hash/fnv"
+	"sort"
+	"sync"
+	"time"
+)
+
+const dateFormat = "2006-01-02"
+
+var (
+	ErrAlreadyPlayed = errors.New("you have already played today's challenge")
+)
+
+// Difficulty is the board everyone plays in the daily challenge.
+var Difficulty = game.Intermediate
+
+// Challenge is the board of one day. Everyone who plays it gets the same
+// mines and the same opening.
+type Challenge struct {
+	// Date is the UTC date of the challenge, formatted as YYYY-MM-DD.
+	Date       string
+	Difficulty game.Difficulty
+	Seed       int64
+	Start      game.Coordinate
+}
+
+func Today() Challenge {
+	return For(time.Now())
+}
+
+// For returns the challenge of the UTC day t falls on.
+func For(t time.Time) Challenge {
+	date := t.UTC().Format(dateFormat)
+
+	h := fnv.New64a()
+	h.Write([]byte("minesshweeper daily " + date))
+
+	return Challenge{
+		Date:       date,
+		Difficulty: Difficulty,
+		Seed:       int64(h.Sum64()),
+		Start:      game.Coordinate{X: Difficulty.Width / 2, Y: Difficulty.Height / 2},
+	}
+}
+
+func (c Challenge) NewGame() (*game.Game, error) {
+	return game.NewWithOpening(c.Difficulty, c.Seed, c.Start)
+}
+
+// Practice returns a board of the same size and opening to practise on. Its
+// mines are laid out from a new seed, so that it gives nothing away about
+// the ranked board.
+func (c Challenge) Practice() Challenge {
+	seed := c.Seed
+	for seed == c.Seed {
+		seed = time.Now().UnixNano()
+	}
+	c.Seed = seed
+	return c
+}
+
+// Attempts makes sure every player gets a single ranked attempt per day.
+// Starting an attempt records an unfinished result, so that attempts that
+// are never finished count as well.
+type Attempts struct {
+	mu      sync.Mutex
+	results leaderboard.Store
+}
+
+func NewAttempts(results leaderboard.Store) *Attempts {
+	return &Attempts{
+		results: results,
+	}
+}
+
+// Start claims the player's ranked attempt at the challenge.
+func (a *Attempts) Start(c Challenge, playerID string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	played, err := a.played(c, playerID)
+	if err != nil {
+		return err
+	}
+	if played {
+		return ErrAlreadyPlayed
+	}
+
+	return a.results.Record(leaderboard.Result{
+		PlayerID:   playerID,
+		Width:      c.Difficulty.Width,
+		Height:     c.Difficulty.Height,
+		Mines:      c.Difficulty.Mines,
+		PlayedAt:   time.Now(),
+		Daily:      c.Date,
+		Unfinished: true,
+	})
+}
+
+// Available reports whether the player can still make a ranked attempt at
+// the challenge.
+func (a *Attempts) Available(c Challenge, playerID string) (bool, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	played, err := a.played(c, playerID)
+	return !played, err
+}
+
+func (a *Attempts) played(c Challenge, playerID string) (bool, error) {
+	results, err := a.results.Results()
+	if err != nil {
+		return false, err
+	}
+
+	for _, r := range results {
+		if r.Daily == c.Date && r.PlayerID == playerID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Ranking returns the ranked attempts at the challenge of the given date.
+// Cleared boards come first, fastest first, followed by everyone who hit a
+// mine in the order they played. Attempts that are still going, or were
+// never finished, are left out.
+func Ranking(results []leaderboard.Result, date string) []leaderboard.Result {
+	var ranked []leaderboard.Result
+	for _, r := range results {
+		if r.Daily == date && !r.Unfinished {
+			ranked = append(ranked, r)
+		}
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		a, b := ranked[i], ranked[j]
+		if a.Won != b.Won {
+			return a.Won
+		}
+		if a.Won && a.Time != b.Time {
+			return a.Time < b.Time
+		}
+		return a.PlayedAt.Before(b.PlayedAt)
+	})
+
+	return ranked
+}
+
+// Streak counts the consecutive days up to today on which a player made a
+// ranked attempt, and on which they cleared the board. A streak stays alive
+// through today until the day is over.
+type Streak struct {
+	Played int
+	Won    int
+}
+
+func StreakOf(results []leaderboard.Result, playerID string, today Challenge) Streak {
+	played := map[string]bool{}
+	won := map[string]bool{}
+	for _, r := range results {
+		if r.PlayerID != playerID || r.Daily == "" {
+			continue
+		}
+		played[r.Daily] = true
+		if r.Won {
+			won[r.Daily] = true
+		}
+	}
+
+	day, err := time.Parse(dateFormat, today.Date)
+	if err != nil {
+		return Streak{}
+	}
+
+	// Until today is played, the streaks count up to yesterday.
+	if !played[today.Date] {
+		day = day.AddDate(0, 0, -1)
+	}
+
+	return Streak{
+		Played: count(played, day),
+		Won:    count(won, day),
+	}
+}
+
+// count counts the days in a row up to and including day.
+func count(days map[string]bool, day time.Time) int {
+	n := 0
+	for days[day.Format(dateFormat)] {
+		n++
+		day = day.AddDate(0, 0, -1)
+	}
+	return n
+}
@@ -0,0 +1,133 @@
+package daily_test
+
+import (
+	"github.com/jboewer/minesshweeper/daily"
+	"github.com/jboewer/minesshweeper/leaderboard"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestFor(t *testing.T) {
+	morning := daily.For(time.Date(2024, 3, 1, 0, 30, 0, 0, time.UTC))
+	evening := daily.For(time.Date(2024, 3, 1, 23, 30, 0, 0, time.UTC))
+	nextDay := daily.For(time.Date(2024, 3, 2, 0, 30, 0, 0, time.UTC))
+
+	assert.Equal(t, "2024-03-01", morning.Date)
+	assert.Equal(t, morning, evening)
+	assert.NotEqual(t, morning.Seed, nextDay.Seed)
+
+	// Days follow UTC, wherever the server is.
+	tz := time.FixedZone("UTC+2", 2*60*60)
+	assert.Equal(t, morning, daily.For(time.Date(2024, 3, 1, 2, 30, 0, 0, tz)))
+}
+
+func TestChallengeNewGame(t *testing.T) {
+	c := daily.For(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+
+	a, err := c.NewGame()
+	assert.NoError(t, err)
+	b, err := c.NewGame()
+	assert.NoError(t, err)
+
+	assert.Equal(t, a.Snapshot(), b.Snapshot())
+	assert.Equal(t, c.Difficulty.Mines, a.GetMineCount())
+	assert.Equal(t, 0, a.Snapshot().Cell(c.Start.X, c.Start.Y).AdjacentMines)
+}
+
+func TestChallengePractice(t *testing.T) {
+	c := daily.For(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+
+	p := c.Practice()
+
+	assert.NotEqual(t, c.Seed, p.Seed)
+	assert.Equal(t, c.Date, p.Date)
+	assert.Equal(t, c.Difficulty, p.Difficulty)
+	assert.Equal(t, c.Start, p.Start)
+}
+
+func TestAttempts(t *testing.T) {
+	c := daily.For(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	store := leaderboard.NewMemoryStore()
+	store.Record(leaderboard.Result{PlayerID: "bob", Daily: c.Date})
+	store.Record(leaderboard.Result{PlayerID: "carol", Daily: "2024-02-29"})
+	attempts := daily.NewAttempts(store)
+
+	t.Run("Once per day", func(t *testing.T) {
+		available, err := attempts.Available(c, "alice")
+		assert.NoError(t, err)
+		assert.True(t, available)
+
+		assert.NoError(t, attempts.Start(c, "alice"))
+		assert.ErrorIs(t, attempts.Start(c, "alice"), daily.ErrAlreadyPlayed)
+
+		available, err = attempts.Available(c, "alice")
+		assert.NoError(t, err)
+		assert.False(t, available)
+	})
+	t.Run("Recorded results", func(t *testing.T) {
+		assert.ErrorIs(t, attempts.Start(c, "bob"), daily.ErrAlreadyPlayed)
+		assert.NoError(t, attempts.Start(c, "carol"))
+	})
+	t.Run("Unfinished attempts outlast the server", func(t *testing.T) {
+		assert.NoError(t, attempts.Start(c, "dave"))
+
+		restarted := daily.NewAttempts(store)
+		assert.ErrorIs(t, restarted.Start(c, "dave"), daily.ErrAlreadyPlayed)
+
+		// Only bob has finished an attempt.
+		results, _ := store.Results()
+		assert.Len(t, daily.Ranking(results, c.Date), 1)
+	})
+}
+
+func TestRanking(t *testing.T) {
+	day := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	results := []leaderboard.Result{
+		{PlayerID: "alice", Won: false, Time: time.Second, PlayedAt: day.Add(2 * time.Hour), Daily: "2024-03-01"},
+		{PlayerID: "bob", Won: true, Time: 90 * time.Second, PlayedAt: day, Daily: "2024-03-01"},
+		{PlayerID: "carol", Won: true, Time: 60 * time.Second, PlayedAt: day, Daily: "2024-03-01"},
+		{PlayerID: "dave", Won: false, Time: time.Minute, PlayedAt: day.Add(time.Hour), Daily: "2024-03-01"},
+		{PlayerID: "erin", Won: true, Time: time.Second, PlayedAt: day, Daily: "2024-02-29"},
+		{PlayerID: "frank", Won: true, Time: time.Second, PlayedAt: day},
+	}
+
+	var players []string
+	for _, r := range daily.Ranking(results, "2024-03-01") {
+		players = append(players, r.PlayerID)
+	}
+
+	assert.Equal(t, []string{"carol", "bob", "dave", "alice"}, players)
+}
+
+func TestStreakOf(t *testing.T) {
+	attempt := func(date string, won bool) leaderboard.Result {
+		return leaderboard.Result{PlayerID: "alice", Won: won, Daily: date}
+	}
+	results := []leaderboard.Result{
+		attempt("2024-02-26", true),
+		attempt("2024-02-28", true),
+		attempt("2024-02-29", false),
+		attempt("2024-03-01", true),
+		{PlayerID: "alice", Won: true},
+		{PlayerID: "bob", Won: true, Daily: "2024-02-27"},
+	}
+
+	t.Run("Played today", func(t *testing.T) {
+		c := daily.For(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+		assert.Equal(t, daily.Streak{Played: 3, Won: 1}, daily.StreakOf(results, "alice", c))
+	})
+	t.Run("Not yet played today", func(t *testing.T) {
+		c := daily.For(time.Date(2024, 3, 2, 12, 0, 0, 0, time.UTC))
+		assert.Equal(t, daily.Streak{Played: 3, Won: 1}, daily.StreakOf(results, "alice", c))
+	})
+	t.Run("Lost today", func(t *testing.T) {
+		lost := append(results, attempt("2024-03-02", false))
+		c := daily.For(time.Date(2024, 3, 2, 12, 0, 0, 0, time.UTC))
+		assert.Equal(t, daily.Streak{Played: 4, Won: 0}, daily.StreakOf(lost, "alice", c))
+	})
+	t.Run("Broken", func(t *testing.T) {
+		c := daily.For(time.Date(2024, 3, 3, 12, 0, 0, 0, time.UTC))
+		assert.Equal(t, daily.Streak{}, daily.StreakOf(results, "alice", c))
+	})
+}
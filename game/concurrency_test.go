@@ -0,0 +1,79 @@
+package game_test
+
+import (
+	"github.com/jboewer/minesshweeper/game"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+)
+
+func TestGame_ConcurrentRevealAndFlag(t *testing.T) {
+	const size = 20
+
+	// Every safe cell is next to one of the mines, so a reveal opens a
+	// single cell. The last column is never revealed, so the game is still
+	// on when the goroutines are done.
+	isMine := func(x, y int) bool { return x%3 == 0 && y%3 == 0 }
+	grid := make(game.Grid, size)
+	for y := range grid {
+		grid[y] = make([]int, size)
+		for x := range grid[y] {
+			if isMine(x, y) {
+				grid[y][x] = 1
+			}
+		}
+	}
+	g, err := game.NewFromGrid(grid)
+	assert.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			for n := 0; n < 200; n++ {
+				x := (i*7 + n*3) % (size - 1)
+				y := (i*5 + n*11) % size
+				if isMine(x, y) {
+					continue
+				}
+
+				switch n % 4 {
+				case 0:
+					res, err := g.Reveal(x, y)
+					assert.NoError(t, err)
+					assert.NotContains(t, []game.RevealOutcome{game.RevealMine, game.RevealGameOver}, res.Outcome)
+				case 1:
+					g.ToggleFlag(x, y)
+				case 2:
+					s := g.Snapshot()
+					assert.Equal(t, size, s.Width)
+					assert.Len(t, s.Cells, size)
+				case 3:
+					g.State()
+					g.GetFlagCount()
+					g.GetGrid()
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Equal(t, game.StatePlaying, g.State())
+	assert.Greater(t, g.Stats().Revealed, 0)
+}
+
+func TestGame_SnapshotIsNotAffectedByLaterMoves(t *testing.T) {
+	g, _ := game.NewFromGrid(game.Grid{
+		{1, 0, 0},
+	})
+
+	before := g.Snapshot()
+	g.Reveal(2, 0)
+	g.PlaceFlag(0, 0)
+
+	assert.Equal(t, game.CellStateHidden, before.Cell(0, 0).State)
+	assert.Equal(t, game.CellStateHidden, before.Cell(2, 0).State)
+	assert.Equal(t, game.StatePlaying, before.State)
+}
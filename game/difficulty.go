@@ -0,0 +1,119 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Difficulty struct {
+	Name   string
+	Width  int
+	Height int
+	Mines  int
+}
+
+var (
+	Beginner     = Difficulty{Name: "Beginner", Width: 9, Height: 9, Mines: 10}
+	Intermediate = Difficulty{Name: "Intermediate", Width: 16, Height: 16, Mines: 40}
+	Expert       = Difficulty{Name: "Expert", Width: 30, Height: 16, Mines: 99}
+)
+
+// Difficulties lists the standard presets from easiest to hardest.
+var Difficulties = []Difficulty{Beginner, Intermediate, Expert}
+
+// DifficultyOf returns the preset matching the board, or a custom
+// difficulty named after its size.
+func DifficultyOf(width, height, mines int) Difficulty {
+	for _, d := range Difficulties {
+		if d.Width == width && d.Height == height && d.Mines == mines {
+			return d
+		}
+	}
+
+	return Difficulty{
+		Name:   fmt.Sprintf("Custom %dx%d/%d", width, height, mines),
+		Width:  width,
+		Height: height,
+		Mines:  mines,
+	}
+}
+
+// ParseDifficulty accepts the name of a preset in any case, or a custom
+// board written as WxH/M, such as 10x10/10.
+func ParseDifficulty(s string) (Difficulty, error) {
+	s = strings.TrimSpace(s)
+	for _, d := range Difficulties {
+		if strings.EqualFold(d.Name, s) {
+			return d, nil
+		}
+	}
+
+	var width, height, mines int
+	if _, err := fmt.Sscanf(strings.ToLower(s), "%dx%d/%d", &width, &height, &mines); err != nil {
+		return Difficulty{}, fmt.Errorf("unknown difficulty %q", s)
+	}
+
+	d := DifficultyOf(width, height, mines)
+	if err := d.Validate(); err != nil {
+		return Difficulty{}, err
+	}
+
+	return d, nil
+}
+
+// Validate reports whether a game can be played on d. A board needs at
+// least one cell without a mine.
+func (d Difficulty) Validate() error {
+	if d.Width <= 0 || d.Height <= 0 || d.Mines < 0 || d.Mines >= d.Width*d.Height {
+		return ErrInvalidFieldSize
+	}
+	return nil
+}
+
+// MaxSide is the widest and tallest board players may ask for.
+const MaxSide = 100
+
+var ErrBoardTooLarge = fmt.Errorf("boards are at most %dx%d", MaxSide, MaxSide)
+
+// CheckSize reports whether players may ask for a board as large as d.
+func (d Difficulty) CheckSize() error {
+	if d.Width > MaxSide || d.Height > MaxSide {
+		return ErrBoardTooLarge
+	}
+	return nil
+}
+
+// NewWithDifficulty returns a game with the size of d and its mines placed.
+func NewWithDifficulty(d Difficulty, opts ...Option) (*Game, error) {
+	g, err := New(d.Width, d.Height, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := g.PlaceRandomMines(d.Mines); err != nil {
+		return nil, err
+	}
+
+	return g, nil
+}
+
+// NewWithOpening returns a game laid out from seed in which start and, if
+// there is room, its neighbours are free of mines. start is revealed
+// already, so every game built from the same arguments begins in the same
+// position.
+func NewWithOpening(d Difficulty, seed int64, start Coordinate) (*Game, error) {
+	g, err := New(d.Width, d.Height, WithSeed(seed))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := g.PlaceRandomMinesAvoiding(d.Mines, start); err != nil {
+		return nil, err
+	}
+
+	if _, err := g.Reveal(start.X, start.Y); err != nil {
+		return nil, err
+	}
+
+	return g, nil
+}
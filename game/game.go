@@ -3,6 +3,7 @@ package game
 import (
 	"errors"
 	"math/rand"
+	"sync"
 	"time"
 )
 
@@ -31,18 +32,36 @@ const (
 	StateLost
 )
 
+// Game is safe for concurrent use. Readers that need a consistent view of
+// the board should take a Snapshot instead of calling several getters.
 type Game struct {
+	mu sync.RWMutex
+
 	gridWidth     int
 	gridHeight    int
 	mines         []Coordinate
 	flags         []Coordinate
 	revealedCells []Coordinate
+	exploded      *Coordinate
 	gameOver      bool
+
+	seed       int64
+	clicks     int
+	startedAt  time.Time
+	finishedAt time.Time
 }
 
 type Option func(*Game)
 
-func New(width, height int) (*Game, error) {
+// WithSeed makes PlaceRandomMines lay out the same board every time it is
+// called with the same seed and board size.
+func WithSeed(seed int64) Option {
+	return func(g *Game) {
+		g.seed = seed
+	}
+}
+
+func New(width, height int, opts ...Option) (*Game, error) {
 	if width <= 0 || height <= 0 {
 		return nil, ErrInvalidFieldSize
 	}
@@ -50,6 +69,11 @@ func New(width, height int) (*Game, error) {
 	g := &Game{
 		gridWidth:  width,
 		gridHeight: height,
+		seed:       time.Now().UnixNano(),
+	}
+
+	for _, opt := range opts {
+		opt(g)
 	}
 
 	return g, nil
@@ -81,7 +105,21 @@ func (g *Game) GetGridHeight() int {
 	return g.gridHeight
 }
 
+func (g *Game) Seed() int64 {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.seed
+}
+
 func (g *Game) PlaceMine(x int, y int) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.placeMine(x, y)
+}
+
+func (g *Game) placeMine(x int, y int) error {
 	if !g.coordinatesInBounds(x, y) {
 		return ErrOutOfBounds
 	}
@@ -95,50 +133,47 @@ func (g *Game) PlaceMine(x int, y int) error {
 }
 
 func (g *Game) GetMineCount() int {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
 	return len(g.mines)
 }
 
+// RevealCell reveals the cell at x, y and returns the number of adjacent
+// mines, or -1 if the game is over or a mine was hit. A flag on the cell is
+// removed first. It is kept for compatibility; Reveal reports the outcome in
+// more detail.
 func (g *Game) RevealCell(x int, y int) int {
-	if g.gameOver {
-		return -1
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.state() == StatePlaying {
+		g.removeFlag(x, y)
 	}
-	if g.cellHasMine(x, y) {
-		g.gameOver = true
+
+	res, err := g.revealAt(x, y)
+	if err != nil {
 		return -1
 	}
 
-	g.revealedCells = append(g.revealedCells, Coordinate{x, y})
-	g.RemoveFlag(x, y)
-
-	n := g.getNumberOfAdjacentMines(x, y)
-	if n > 0 {
-		return n
+	switch res.Outcome {
+	case RevealMine, RevealGameOver:
+		return -1
+	case RevealAlreadyRevealed:
+		return g.getNumberOfAdjacentMines(x, y)
 	}
 
-	g.revealAdjacentCells(x, y)
-
-	return 0
+	return res.Revealed[0].AdjacentMines
 }
 
-func (g *Game) revealAdjacentCells(x int, y int) {
-	for x2 := x - 1; x2 <= x+1; x2++ {
-		for y2 := y - 1; y2 <= y+1; y2++ {
-			if !g.coordinatesInBounds(x2, y2) {
-				continue
-			}
-
-			if g.cellIsRevealed(x2, y2) {
-				continue
-			}
+func (g *Game) RemoveFlag(x int, y int) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
 
-			if !g.cellHasMine(x2, y2) && !g.cellHasFlag(x2, y2) {
-				g.RevealCell(x2, y2)
-			}
-		}
-	}
+	g.removeFlag(x, y)
 }
 
-func (g *Game) RemoveFlag(x int, y int) {
+func (g *Game) removeFlag(x int, y int) {
 	for i, f := range g.flags {
 		if f.X == x && f.Y == y {
 			g.flags = append(g.flags[:i], g.flags[i+1:]...)
@@ -148,6 +183,13 @@ func (g *Game) RemoveFlag(x int, y int) {
 }
 
 func (g *Game) State() State {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.state()
+}
+
+func (g *Game) state() State {
 	if g.gameOver {
 		return StateLost
 	}
@@ -182,6 +224,13 @@ func (g *Game) coordinatesInBounds(x int, y int) bool {
 }
 
 func (g *Game) PlaceFlag(x int, y int) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.placeFlag(x, y)
+}
+
+func (g *Game) placeFlag(x int, y int) error {
 	if !g.coordinatesInBounds(x, y) {
 		return ErrOutOfBounds
 	}
@@ -192,11 +241,18 @@ func (g *Game) PlaceFlag(x int, y int) error {
 }
 
 func (g *Game) GetFlagCount() int {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
 	return len(g.flags)
 }
 
 func (g *Game) checkWinCondition() bool {
-	if len(g.flags) != len(g.mines) {
+	if len(g.revealedCells) == g.gridWidth*g.gridHeight-len(g.mines) {
+		return true
+	}
+
+	if len(g.mines) == 0 || len(g.flags) != len(g.mines) {
 		return false
 	}
 
@@ -227,6 +283,9 @@ func (g *Game) getNumberOfAdjacentMines(revealX int, revealY int) int {
 }
 
 func (g *Game) PlaceMines(grid Grid) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
 	if grid.GetHeight() != g.gridHeight || grid.GetWidth() != g.gridWidth {
 		return ErrInvalidFieldSize
 	}
@@ -237,7 +296,7 @@ func (g *Game) PlaceMines(grid Grid) error {
 	for y, row := range grid {
 		for x, cell := range row {
 			if cell == 1 {
-				err := g.PlaceMine(x, y)
+				err := g.placeMine(x, y)
 				if err != nil {
 					return err
 				}
@@ -247,21 +306,25 @@ func (g *Game) PlaceMines(grid Grid) error {
 	return nil
 }
 
+// GetGrid returns the visible board encoded as integers, where the Cell
+// constants are special values and 0 to 8 are adjacent mine counts. New code
+// should use Snapshot instead.
 func (g *Game) GetGrid() Grid {
-	grid := newGrid(g.gridWidth, g.gridHeight)
-	grid.SetAll(CellUnrevealed)
-
-	for _, c := range g.revealedCells {
-		grid.Set(c.X, c.Y, g.getNumberOfAdjacentMines(c.X, c.Y))
-	}
-
-	for _, f := range g.flags {
-		grid.Set(f.X, f.Y, CellFlag)
-	}
-
-	if g.State() == StateLost {
-		for _, m := range g.mines {
-			grid.Set(m.X, m.Y, CellMine)
+	s := g.Snapshot()
+	grid := newGrid(s.Width, s.Height)
+
+	for y, row := range s.Cells {
+		for x, c := range row {
+			switch {
+			case c.State == CellStateRevealed:
+				grid.Set(x, y, c.AdjacentMines)
+			case c.State == CellStateMine && s.State == StateLost:
+				grid.Set(x, y, CellMine)
+			case c.State == CellStateFlagged:
+				grid.Set(x, y, CellFlag)
+			default:
+				grid.Set(x, y, CellUnrevealed)
+			}
 		}
 	}
 
@@ -278,36 +341,92 @@ func (g *Game) cellIsRevealed(x int, y int) bool {
 }
 
 func (g *Game) ToggleFlag(x int, y int) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.state() == StatePlaying {
+		g.clicks++
+	}
+
 	if g.cellHasFlag(x, y) {
-		g.RemoveFlag(x, y)
+		g.removeFlag(x, y)
 	} else {
-		g.PlaceFlag(x, y)
+		g.placeFlag(x, y)
 	}
+
+	g.updateTimer()
 }
 
 func (g *Game) PlaceRandomMines(count int) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.placeRandomMines(count)
+}
+
+func (g *Game) placeRandomMines(count int) error {
 	if count < 0 || count > g.gridWidth*g.gridHeight {
 		return ErrInvalidFieldSize
 	}
 
-	rand.Seed(time.Now().UnixNano())
+	return g.placeMinesFrom(g.shuffledPositions(nil), count)
+}
+
+// PlaceRandomMinesAvoiding places count mines like PlaceRandomMines, but
+// keeps safe and, if there is room for it, its neighbours free of mines. This
+// guarantees that revealing safe as the first move opens the board.
+func (g *Game) PlaceRandomMinesAvoiding(count int, safe Coordinate) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if !g.coordinatesInBounds(safe.X, safe.Y) {
+		return ErrOutOfBounds
+	}
+	if count < 0 || count > g.gridWidth*g.gridHeight-1 {
+		return ErrInvalidFieldSize
+	}
+
+	positions := g.shuffledPositions(func(c Coordinate) bool {
+		return abs(c.X-safe.X) <= 1 && abs(c.Y-safe.Y) <= 1
+	})
+	if count > len(positions) {
+		positions = g.shuffledPositions(func(c Coordinate) bool {
+			return c == safe
+		})
+	}
+
+	return g.placeMinesFrom(positions, count)
+}
+
+// shuffledPositions returns every coordinate on the board for which exclude
+// returns false, in an order derived from the game's seed.
+func (g *Game) shuffledPositions(exclude func(Coordinate) bool) []Coordinate {
+	rng := rand.New(rand.NewSource(g.seed))
 
 	availablePositions := make([]Coordinate, 0, g.gridWidth*g.gridHeight)
 	for x := 0; x < g.gridWidth; x++ {
 		for y := 0; y < g.gridHeight; y++ {
-			availablePositions = append(availablePositions, Coordinate{x, y})
+			c := Coordinate{x, y}
+			if exclude != nil && exclude(c) {
+				continue
+			}
+			availablePositions = append(availablePositions, c)
 		}
 	}
 
 	// Shuffle the list of positions
-	rand.Shuffle(len(availablePositions), func(i, j int) {
+	rng.Shuffle(len(availablePositions), func(i, j int) {
 		availablePositions[i], availablePositions[j] = availablePositions[j], availablePositions[i]
 	})
 
-	// Place mines in the first 'count' positions
+	return availablePositions
+}
+
+// placeMinesFrom places mines in the first count positions.
+func (g *Game) placeMinesFrom(positions []Coordinate, count int) error {
 	for i := 0; i < count; i++ {
-		pos := availablePositions[i]
-		err := g.PlaceMine(pos.X, pos.Y)
+		pos := positions[i]
+		err := g.placeMine(pos.X, pos.Y)
 		if err != nil {
 			return err
 		}
@@ -316,10 +435,46 @@ func (g *Game) PlaceRandomMines(count int) error {
 	return nil
 }
 
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// Reset lays out a new board from a new seed, with as many mines as the
+// current one.
 func (g *Game) Reset() {
+	g.Reseed(time.Now().UnixNano())
+}
+
+// Reseed lays out the board seed gives for the game's size and number of
+// mines, and starts over on it.
+func (g *Game) Reseed(seed int64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	count := len(g.mines)
+	g.restart()
 	g.mines = nil
+	g.seed = seed
+	g.placeRandomMines(count)
+}
+
+// Restart starts over on the same board.
+func (g *Game) Restart() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.restart()
+}
+
+func (g *Game) restart() {
 	g.flags = nil
 	g.revealedCells = nil
+	g.exploded = nil
 	g.gameOver = false
-	g.PlaceRandomMines(10)
+	g.clicks = 0
+	g.startedAt = time.Time{}
+	g.finishedAt = time.Time{}
 }
@@ -267,6 +267,26 @@ func TestMinePlacementMap_Validate(t *testing.T) {
 	})
 }
 
+func TestGameIsNotWonBeforeAnyMoveWithoutMines(t *testing.T) {
+	g, _ := game.New(2, 2)
+	assert.Equal(t, game.StatePlaying, g.State())
+
+	g.Reveal(0, 0)
+	assert.Equal(t, game.StateWon, g.State())
+}
+
+func TestGame_RevealCell_RemovesTheFlag(t *testing.T) {
+	g, _ := game.NewFromGrid(game.Grid{
+		{1, 0, 0},
+		{0, 0, 0},
+	})
+	g.PlaceFlag(1, 0)
+
+	assert.Equal(t, 1, g.RevealCell(1, 0))
+	assert.Equal(t, 0, g.GetFlagCount())
+	assert.Equal(t, game.CellStateRevealed, g.Snapshot().Cell(1, 0).State)
+}
+
 func TestGame_RevealCell_ItReturnsTheNumberOfAdjacentMines(t *testing.T) {
 	t.Run("No mines", func(t *testing.T) {
 		g, _ := game.New(5, 5)
@@ -408,6 +428,66 @@ func TestItRevealsAdjacentCellsIfCellHasNone(t *testing.T) {
 	assertEqualGrid(t, expected, g.GetGrid())
 }
 
+func TestGame_Chord(t *testing.T) {
+	grid := game.Grid{
+		{1, 0, 0, 0},
+		{0, 0, 0, 0},
+		{0, 0, 0, 1},
+	}
+
+	t.Run("Opens neighbours", func(t *testing.T) {
+		g, _ := game.NewFromGrid(grid)
+		g.Reveal(1, 1)
+		g.PlaceFlag(0, 0)
+
+		res, err := g.Chord(1, 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, game.RevealSafe, res.Outcome)
+		assert.Equal(t, game.StateWon, res.State)
+		assert.Len(t, res.Revealed, 9)
+		assert.Equal(t, 2, g.Stats().Clicks)
+	})
+	t.Run("Needs enough flags", func(t *testing.T) {
+		g, _ := game.NewFromGrid(grid)
+		g.Reveal(1, 1)
+
+		res, err := g.Chord(1, 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, game.RevealAlreadyRevealed, res.Outcome)
+		assert.Empty(t, res.Revealed)
+		assert.Equal(t, game.StatePlaying, res.State)
+	})
+	t.Run("Wrong flag hits a mine", func(t *testing.T) {
+		g, _ := game.NewFromGrid(grid)
+		g.Reveal(1, 1)
+		g.PlaceFlag(1, 0)
+
+		res, err := g.Chord(1, 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, game.RevealMine, res.Outcome)
+		assert.Equal(t, game.StateLost, res.State)
+	})
+	t.Run("Hidden cell", func(t *testing.T) {
+		g, _ := game.NewFromGrid(grid)
+
+		res, err := g.Chord(1, 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, game.RevealSafe, res.Outcome)
+		assert.Equal(t, []game.RevealedCell{{Coordinate: game.Coordinate{X: 1, Y: 1}, AdjacentMines: 1}}, res.Revealed)
+	})
+	t.Run("Out of bounds", func(t *testing.T) {
+		g, _ := game.NewFromGrid(grid)
+
+		_, err := g.Chord(4, 0)
+
+		assert.ErrorIs(t, err, game.ErrOutOfBounds)
+	})
+}
+
 func assertEqualGrid(t *testing.T, expected, actual game.Grid) {
 	t.Helper()
 
@@ -415,3 +495,333 @@ func assertEqualGrid(t *testing.T, expected, actual game.Grid) {
 		t.Errorf("Expected %v, got %v", expected, actual)
 	}
 }
+
+func TestGame_Snapshot(t *testing.T) {
+	t.Run("Hidden and revealed cells", func(t *testing.T) {
+		g, _ := game.NewFromGrid(game.Grid{
+			{1, 0, 0},
+		})
+		g.RevealCell(1, 0)
+
+		s := g.Snapshot()
+
+		assert.Equal(t, 3, s.Width)
+		assert.Equal(t, 1, s.Height)
+		assert.Equal(t, game.StatePlaying, s.State)
+		assert.Equal(t, game.CellView{State: game.CellStateHidden}, s.Cell(0, 0))
+		assert.Equal(t, game.CellView{State: game.CellStateRevealed, AdjacentMines: 1}, s.Cell(1, 0))
+		assert.Equal(t, game.CellView{State: game.CellStateHidden}, s.Cell(2, 0))
+	})
+	t.Run("Mines stay hidden while playing", func(t *testing.T) {
+		g, _ := game.NewFromGrid(game.Grid{
+			{1, 0, 1},
+		})
+		g.PlaceFlag(0, 0)
+
+		s := g.Snapshot()
+
+		assert.Equal(t, game.CellView{State: game.CellStateFlagged}, s.Cell(0, 0))
+		assert.Equal(t, game.CellView{State: game.CellStateHidden}, s.Cell(2, 0))
+	})
+	t.Run("Lost game shows mistakes", func(t *testing.T) {
+		g, _ := game.NewFromGrid(game.Grid{
+			{1, 0, 1, 1},
+		})
+		g.PlaceFlag(0, 0)
+		g.PlaceFlag(1, 0)
+		g.RevealCell(2, 0)
+
+		s := g.Snapshot()
+
+		assert.Equal(t, game.StateLost, s.State)
+		assert.Equal(t, game.CellView{State: game.CellStateFlagged, FlaggedMine: true}, s.Cell(0, 0))
+		assert.Equal(t, game.CellView{State: game.CellStateFlagged, WrongFlag: true}, s.Cell(1, 0))
+		assert.Equal(t, game.CellView{State: game.CellStateMine, Exploded: true}, s.Cell(2, 0))
+		assert.Equal(t, game.CellView{State: game.CellStateMine}, s.Cell(3, 0))
+	})
+	t.Run("Won game shows the mines", func(t *testing.T) {
+		g, _ := game.NewFromGrid(game.Grid{
+			{1, 0, 1},
+		})
+		g.PlaceFlag(0, 0)
+		g.RevealCell(1, 0)
+
+		s := g.Snapshot()
+
+		assert.Equal(t, game.StateWon, s.State)
+		assert.Equal(t, game.CellView{State: game.CellStateFlagged, FlaggedMine: true}, s.Cell(0, 0))
+		assert.Equal(t, game.CellView{State: game.CellStateMine}, s.Cell(2, 0))
+	})
+}
+
+func TestGame_GetGrid_LostGameShowsMines(t *testing.T) {
+	g, _ := game.NewFromGrid(game.Grid{
+		{1, 0, 1, 1},
+	})
+	g.PlaceFlag(0, 0)
+	g.PlaceFlag(1, 0)
+	g.RevealCell(2, 0)
+
+	expected := game.Grid{
+		{game.CellFlag, game.CellFlag, game.CellMine, game.CellMine},
+	}
+	assertEqualGrid(t, expected, g.GetGrid())
+}
+
+func TestGame_Reveal(t *testing.T) {
+	t.Run("Safe cell with adjacent mines", func(t *testing.T) {
+		g, _ := game.NewFromGrid(game.Grid{
+			{1, 0, 0},
+			{0, 0, 0},
+		})
+
+		res, err := g.Reveal(1, 0)
+
+		assert.NoError(t, err)
+		assert.Equal(t, game.RevealSafe, res.Outcome)
+		assert.Equal(t, []game.RevealedCell{
+			{Coordinate: game.Coordinate{X: 1, Y: 0}, AdjacentMines: 1},
+		}, res.Revealed)
+		assert.Equal(t, game.StatePlaying, res.State)
+	})
+	t.Run("Flood fill lists every opened cell", func(t *testing.T) {
+		g, _ := game.NewFromGrid(game.Grid{
+			{0, 0, 1},
+		})
+
+		res, err := g.Reveal(0, 0)
+
+		assert.NoError(t, err)
+		assert.Equal(t, game.RevealSafe, res.Outcome)
+		assert.Equal(t, []game.RevealedCell{
+			{Coordinate: game.Coordinate{X: 0, Y: 0}, AdjacentMines: 0},
+			{Coordinate: game.Coordinate{X: 1, Y: 0}, AdjacentMines: 1},
+		}, res.Revealed)
+		assert.Equal(t, game.StateWon, res.State)
+	})
+	t.Run("Mine", func(t *testing.T) {
+		g, _ := game.NewFromGrid(game.Grid{
+			{1, 0},
+		})
+
+		res, err := g.Reveal(0, 0)
+
+		assert.NoError(t, err)
+		assert.Equal(t, game.RevealMine, res.Outcome)
+		assert.Empty(t, res.Revealed)
+		assert.Equal(t, game.StateLost, res.State)
+	})
+	t.Run("Flagged cell is ignored", func(t *testing.T) {
+		g, _ := game.NewFromGrid(game.Grid{
+			{1, 0, 1},
+		})
+		g.PlaceFlag(0, 0)
+
+		res, err := g.Reveal(0, 0)
+
+		assert.NoError(t, err)
+		assert.Equal(t, game.RevealFlagged, res.Outcome)
+		assert.Equal(t, game.StatePlaying, res.State)
+	})
+	t.Run("Already revealed", func(t *testing.T) {
+		g, _ := game.NewFromGrid(game.Grid{
+			{1, 0, 0},
+		})
+		g.Reveal(1, 0)
+
+		res, err := g.Reveal(1, 0)
+
+		assert.NoError(t, err)
+		assert.Equal(t, game.RevealAlreadyRevealed, res.Outcome)
+		assert.Empty(t, res.Revealed)
+	})
+	t.Run("Game over", func(t *testing.T) {
+		g, _ := game.NewFromGrid(game.Grid{
+			{1, 0},
+		})
+		g.Reveal(0, 0)
+
+		res, err := g.Reveal(1, 0)
+
+		assert.NoError(t, err)
+		assert.Equal(t, game.RevealGameOver, res.Outcome)
+		assert.Equal(t, game.StateLost, res.State)
+	})
+	t.Run("Out of bounds", func(t *testing.T) {
+		g, _ := game.New(3, 3)
+
+		_, err := g.Reveal(3, 0)
+		assert.ErrorIs(t, err, game.ErrOutOfBounds)
+		_, err = g.Reveal(0, -1)
+		assert.ErrorIs(t, err, game.ErrOutOfBounds)
+	})
+}
+
+func TestGameIsWonWhenAllSafeCellsRevealed(t *testing.T) {
+	g, _ := game.NewFromGrid(game.Grid{
+		{1, 0, 0},
+		{0, 0, 0},
+	})
+
+	g.Reveal(2, 1)
+	assert.Equal(t, game.StatePlaying, g.State())
+
+	g.Reveal(1, 0)
+	g.Reveal(0, 1)
+	assert.Equal(t, game.StateWon, g.State())
+}
+
+func TestGame_PlaceRandomMines_SameSeedSameBoard(t *testing.T) {
+	g1, _ := game.New(9, 9, game.WithSeed(42))
+	g2, _ := game.New(9, 9, game.WithSeed(42))
+
+	assert.NoError(t, g1.PlaceRandomMines(10))
+	assert.NoError(t, g2.PlaceRandomMines(10))
+	assert.Equal(t, int64(42), g1.Seed())
+
+	g1.RevealCell(0, 0)
+	g2.RevealCell(0, 0)
+	assert.Equal(t, g1.Snapshot(), g2.Snapshot())
+}
+
+func TestGame_Reset(t *testing.T) {
+	t.Run("Keeps the number of mines", func(t *testing.T) {
+		g, _ := game.NewWithDifficulty(game.Expert)
+		g.RevealCell(0, 0)
+
+		g.Reset()
+
+		assert.Equal(t, 99, g.GetMineCount())
+		assert.Equal(t, game.StatePlaying, g.State())
+		assert.Zero(t, g.Stats().Revealed)
+	})
+	t.Run("Reseed lays out the board of the seed", func(t *testing.T) {
+		g, _ := game.NewWithDifficulty(game.Beginner, game.WithSeed(1))
+		want, _ := game.NewWithDifficulty(game.Beginner, game.WithSeed(42))
+
+		g.Reseed(42)
+
+		assert.Equal(t, int64(42), g.Seed())
+		g.RevealCell(0, 0)
+		want.RevealCell(0, 0)
+		assert.Equal(t, want.Snapshot(), g.Snapshot())
+	})
+	t.Run("Restart keeps the board", func(t *testing.T) {
+		g, _ := game.NewFromGrid(game.Grid{
+			{1, 0, 0},
+			{0, 0, 0},
+		})
+		g.PlaceFlag(1, 0)
+		g.RevealCell(0, 0)
+
+		g.Restart()
+
+		assert.Equal(t, game.StatePlaying, g.State())
+		assert.Zero(t, g.GetFlagCount())
+		assert.Zero(t, g.Stats().Clicks)
+		g.RevealCell(0, 0)
+		assert.Equal(t, game.StateLost, g.State())
+	})
+}
+
+func TestGame_PlaceRandomMinesAvoiding(t *testing.T) {
+	t.Run("Keeps the cell and its neighbours free", func(t *testing.T) {
+		for seed := int64(0); seed < 20; seed++ {
+			g, _ := game.New(5, 5, game.WithSeed(seed))
+			assert.NoError(t, g.PlaceRandomMinesAvoiding(16, game.Coordinate{X: 2, Y: 2}))
+
+			res, err := g.Reveal(2, 2)
+			assert.NoError(t, err)
+			assert.Equal(t, game.RevealSafe, res.Outcome)
+			assert.Equal(t, 0, res.Revealed[0].AdjacentMines)
+		}
+	})
+	t.Run("Keeps only the cell free when the board is crowded", func(t *testing.T) {
+		g, _ := game.New(3, 3, game.WithSeed(1))
+		assert.NoError(t, g.PlaceRandomMinesAvoiding(8, game.Coordinate{X: 0, Y: 0}))
+
+		assert.Equal(t, 3, g.RevealCell(0, 0))
+		assert.Equal(t, game.StateWon, g.State())
+	})
+	t.Run("Too many mines", func(t *testing.T) {
+		g, _ := game.New(3, 3)
+		assert.ErrorIs(t, g.PlaceRandomMinesAvoiding(9, game.Coordinate{X: 0, Y: 0}), game.ErrInvalidFieldSize)
+	})
+}
+
+func TestGame_Stats(t *testing.T) {
+	t.Run("3BV", func(t *testing.T) {
+		opening, _ := game.NewFromGrid(game.Grid{
+			{0, 0, 1, 0},
+		})
+		numbersOnly, _ := game.NewFromGrid(game.Grid{
+			{0, 0, 0},
+			{0, 1, 0},
+			{0, 0, 0},
+		})
+
+		// The opening on the left plus the isolated 1 on the right
+		assert.Equal(t, 2, opening.Stats().ThreeBV)
+		assert.Equal(t, 8, numbersOnly.Stats().ThreeBV)
+	})
+	t.Run("Clicks and progress", func(t *testing.T) {
+		g, _ := game.NewFromGrid(game.Grid{
+			{1, 0, 0, 1},
+		})
+
+		assert.Zero(t, g.Stats().Elapsed)
+
+		g.ToggleFlag(0, 0)
+		g.Reveal(1, 0)
+
+		s := g.Stats()
+		assert.Equal(t, 2, s.Clicks)
+		assert.Equal(t, 1, s.Revealed)
+		assert.Equal(t, 2, s.SafeCells)
+		assert.Equal(t, 1.0, s.Efficiency())
+		assert.Zero(t, game.Stats{}.Efficiency())
+	})
+	t.Run("Timer stops when the game ends", func(t *testing.T) {
+		g, _ := game.NewFromGrid(game.Grid{
+			{1, 0},
+		})
+
+		g.Reveal(0, 0)
+		elapsed := g.Stats().Elapsed
+		g.Reveal(1, 0)
+
+		assert.Equal(t, elapsed, g.Stats().Elapsed)
+		assert.Equal(t, 1, g.Stats().Clicks)
+	})
+}
+
+func TestDifficultyOf(t *testing.T) {
+	assert.Equal(t, game.Expert, game.DifficultyOf(30, 16, 99))
+	assert.Equal(t, "Custom 10x10/10", game.DifficultyOf(10, 10, 10).Name)
+}
+
+func TestDifficulty_CheckSize(t *testing.T) {
+	assert.NoError(t, game.DifficultyOf(100, 100, 10).CheckSize())
+	assert.ErrorIs(t, game.DifficultyOf(101, 10, 10).CheckSize(), game.ErrBoardTooLarge)
+	assert.ErrorIs(t, game.DifficultyOf(10, 100000, 10).CheckSize(), game.ErrBoardTooLarge)
+}
+
+func TestParseDifficulty(t *testing.T) {
+	d, err := game.ParseDifficulty("expert")
+	assert.NoError(t, err)
+	assert.Equal(t, game.Expert, d)
+
+	d, err = game.ParseDifficulty("20x10/30")
+	assert.NoError(t, err)
+	assert.Equal(t, game.DifficultyOf(20, 10, 30), d)
+
+	d, err = game.ParseDifficulty("9x9/10")
+	assert.NoError(t, err)
+	assert.Equal(t, game.Beginner, d)
+
+	_, err = game.ParseDifficulty("hard")
+	assert.Error(t, err)
+
+	_, err = game.ParseDifficulty("3x3/9")
+	assert.ErrorIs(t, err, game.ErrInvalidFieldSize)
+}
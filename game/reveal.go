@@ -0,0 +1,176 @@
+package game
+
+import "time"
+
+type RevealOutcome int
+
+const (
+	// RevealSafe means at least one cell was opened.
+	RevealSafe RevealOutcome = iota
+	// RevealMine means the cell held a mine and the game is lost.
+	RevealMine
+	// RevealFlagged means the cell is flagged and was left untouched.
+	RevealFlagged
+	// RevealAlreadyRevealed means the cell was open before the call.
+	RevealAlreadyRevealed
+	// RevealGameOver means the game had already ended.
+	RevealGameOver
+)
+
+type RevealedCell struct {
+	Coordinate
+	AdjacentMines int
+}
+
+type RevealResult struct {
+	Outcome RevealOutcome
+	// Revealed lists the cells opened by this call, starting with the
+	// requested cell and followed by any cells opened by the flood fill.
+	Revealed []RevealedCell
+	State    State
+}
+
+// Reveal opens the cell at x, y. Cells without adjacent mines open their
+// neighbours as well.
+func (g *Game) Reveal(x int, y int) (RevealResult, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.revealAt(x, y)
+}
+
+func (g *Game) revealAt(x int, y int) (RevealResult, error) {
+	if !g.coordinatesInBounds(x, y) {
+		return RevealResult{}, ErrOutOfBounds
+	}
+
+	res := RevealResult{Outcome: g.reveal(x, y)}
+	if res.Outcome == RevealSafe {
+		res.Revealed = g.floodFill(x, y)
+	}
+	if res.Outcome != RevealGameOver {
+		g.clicks++
+	}
+	if g.startedAt.IsZero() && (res.Outcome == RevealSafe || res.Outcome == RevealMine) {
+		g.startedAt = time.Now()
+	}
+	g.updateTimer()
+	res.State = g.state()
+
+	return res, nil
+}
+
+func (g *Game) reveal(x int, y int) RevealOutcome {
+	if g.state() != StatePlaying {
+		return RevealGameOver
+	}
+	if g.cellIsRevealed(x, y) {
+		return RevealAlreadyRevealed
+	}
+	if g.cellHasFlag(x, y) {
+		return RevealFlagged
+	}
+	if g.cellHasMine(x, y) {
+		g.exploded = &Coordinate{x, y}
+		g.gameOver = true
+		return RevealMine
+	}
+
+	return RevealSafe
+}
+
+func (g *Game) floodFill(x int, y int) []RevealedCell {
+	var revealed []RevealedCell
+
+	queue := []Coordinate{{x, y}}
+	g.revealedCells = append(g.revealedCells, Coordinate{x, y})
+
+	for len(queue) > 0 {
+		c := queue[0]
+		queue = queue[1:]
+
+		n := g.getNumberOfAdjacentMines(c.X, c.Y)
+		revealed = append(revealed, RevealedCell{Coordinate: c, AdjacentMines: n})
+		if n > 0 {
+			continue
+		}
+
+		for x2 := c.X - 1; x2 <= c.X+1; x2++ {
+			for y2 := c.Y - 1; y2 <= c.Y+1; y2++ {
+				if !g.coordinatesInBounds(x2, y2) {
+					continue
+				}
+
+				if g.cellIsRevealed(x2, y2) || g.cellHasFlag(x2, y2) || g.cellHasMine(x2, y2) {
+					continue
+				}
+
+				g.revealedCells = append(g.revealedCells, Coordinate{x2, y2})
+				queue = append(queue, Coordinate{x2, y2})
+			}
+		}
+	}
+
+	return revealed
+}
+
+// Chord opens all hidden neighbours of the revealed cell at x, y, provided
+// the player has flagged as many of them as the cell has adjacent mines. A
+// wrong flag makes the chord hit a mine. Chording a hidden cell reveals it.
+func (g *Game) Chord(x int, y int) (RevealResult, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if !g.coordinatesInBounds(x, y) {
+		return RevealResult{}, ErrOutOfBounds
+	}
+	if g.state() == StatePlaying && !g.cellIsRevealed(x, y) {
+		return g.revealAt(x, y)
+	}
+
+	res := RevealResult{Outcome: RevealAlreadyRevealed}
+	if g.state() != StatePlaying {
+		res.Outcome = RevealGameOver
+	} else if g.adjacentFlags(x, y) == g.getNumberOfAdjacentMines(x, y) {
+		g.chord(x, y, &res)
+	}
+
+	if res.Outcome != RevealGameOver {
+		g.clicks++
+	}
+	g.updateTimer()
+	res.State = g.state()
+
+	return res, nil
+}
+
+func (g *Game) chord(x int, y int, res *RevealResult) {
+	for x2 := x - 1; x2 <= x+1; x2++ {
+		for y2 := y - 1; y2 <= y+1; y2++ {
+			if !g.coordinatesInBounds(x2, y2) {
+				continue
+			}
+
+			switch g.reveal(x2, y2) {
+			case RevealSafe:
+				res.Outcome = RevealSafe
+				res.Revealed = append(res.Revealed, g.floodFill(x2, y2)...)
+			case RevealMine:
+				res.Outcome = RevealMine
+				return
+			}
+		}
+	}
+}
+
+func (g *Game) adjacentFlags(x int, y int) int {
+	flags := 0
+	for x2 := x - 1; x2 <= x+1; x2++ {
+		for y2 := y - 1; y2 <= y+1; y2++ {
+			if (x2 != x || y2 != y) && g.cellHasFlag(x2, y2) {
+				flags++
+			}
+		}
+	}
+	return flags
+}
@@ -0,0 +1,90 @@
+package game
+
+type CellState int
+
+const (
+	CellStateHidden CellState = iota
+	CellStateRevealed
+	CellStateFlagged
+	CellStateMine
+)
+
+// CellView describes a single cell as a player is allowed to see it. Mine
+// positions are only exposed once the game is over.
+type CellView struct {
+	State         CellState
+	AdjacentMines int
+	// Exploded marks the mine that ended the game.
+	Exploded bool
+	// WrongFlag marks a flag that was placed on a safe cell. It is only set
+	// once the game is over.
+	WrongFlag bool
+	// FlaggedMine marks a flag that correctly covers a mine. It is only set
+	// once the game is over.
+	FlaggedMine bool
+}
+
+type Snapshot struct {
+	Width     int
+	Height    int
+	State     State
+	MineCount int
+	FlagCount int
+	Cells     [][]CellView
+}
+
+func (s Snapshot) Cell(x, y int) CellView {
+	return s.Cells[y][x]
+}
+
+// Snapshot returns a copy of the visible board. It shares no memory with the
+// game, so it can be rendered without holding any locks.
+func (g *Game) Snapshot() Snapshot {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	state := g.state()
+
+	s := Snapshot{
+		Width:     g.gridWidth,
+		Height:    g.gridHeight,
+		State:     state,
+		MineCount: len(g.mines),
+		FlagCount: len(g.flags),
+		Cells:     make([][]CellView, g.gridHeight),
+	}
+	for y := range s.Cells {
+		s.Cells[y] = make([]CellView, g.gridWidth)
+	}
+
+	for _, c := range g.revealedCells {
+		s.Cells[c.Y][c.X] = CellView{
+			State:         CellStateRevealed,
+			AdjacentMines: g.getNumberOfAdjacentMines(c.X, c.Y),
+		}
+	}
+
+	gameOver := state != StatePlaying
+
+	for _, f := range g.flags {
+		s.Cells[f.Y][f.X] = CellView{
+			State:       CellStateFlagged,
+			WrongFlag:   gameOver && !g.cellHasMine(f.X, f.Y),
+			FlaggedMine: gameOver && g.cellHasMine(f.X, f.Y),
+		}
+	}
+
+	if gameOver {
+		for _, m := range g.mines {
+			if g.cellHasFlag(m.X, m.Y) {
+				continue
+			}
+			s.Cells[m.Y][m.X] = CellView{
+				State:    CellStateMine,
+				Exploded: g.exploded != nil && *g.exploded == m,
+			}
+		}
+	}
+
+	return s
+}
@@ -0,0 +1,128 @@
+package game
+
+import "time"
+
+type Stats struct {
+	// Clicks counts every reveal and flag action made while playing.
+	Clicks int
+	// ThreeBV is the minimum number of clicks needed to clear the board
+	// without flagging.
+	ThreeBV   int
+	Revealed  int
+	SafeCells int
+	Elapsed   time.Duration
+}
+
+// ThreeBVPerSecond returns the 3BV solved per second of play, the usual
+// measure of speed across boards of different difficulty.
+func (s Stats) ThreeBVPerSecond() float64 {
+	if s.Elapsed <= 0 {
+		return 0
+	}
+	return float64(s.ThreeBV) / s.Elapsed.Seconds()
+}
+
+// Efficiency returns the 3BV as a share of the clicks made. It can go above
+// 1 when chording saves clicks.
+func (s Stats) Efficiency() float64 {
+	if s.Clicks == 0 {
+		return 0
+	}
+	return float64(s.ThreeBV) / float64(s.Clicks)
+}
+
+func (g *Game) Stats() Stats {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return Stats{
+		Clicks:    g.clicks,
+		ThreeBV:   g.threeBV(),
+		Revealed:  len(g.revealedCells),
+		SafeCells: g.gridWidth*g.gridHeight - len(g.mines),
+		Elapsed:   g.elapsed(),
+	}
+}
+
+func (g *Game) elapsed() time.Duration {
+	switch {
+	case g.startedAt.IsZero():
+		return 0
+	case g.finishedAt.IsZero():
+		return time.Since(g.startedAt)
+	default:
+		return g.finishedAt.Sub(g.startedAt)
+	}
+}
+
+// updateTimer stops the clock once the game has ended.
+func (g *Game) updateTimer() {
+	if g.state() == StatePlaying {
+		g.finishedAt = time.Time{}
+		return
+	}
+
+	if g.finishedAt.IsZero() && !g.startedAt.IsZero() {
+		g.finishedAt = time.Now()
+	}
+}
+
+// threeBV counts every opening (connected area of cells without adjacent
+// mines) plus every numbered cell that does not border an opening.
+func (g *Game) threeBV() int {
+	counts := newGrid(g.gridWidth, g.gridHeight)
+	for _, m := range g.mines {
+		counts.Set(m.X, m.Y, CellMine)
+	}
+	for y := 0; y < g.gridHeight; y++ {
+		for x := 0; x < g.gridWidth; x++ {
+			if counts.Get(x, y) != CellMine {
+				counts.Set(x, y, g.getNumberOfAdjacentMines(x, y))
+			}
+		}
+	}
+
+	marked := make([][]bool, g.gridHeight)
+	for y := range marked {
+		marked[y] = make([]bool, g.gridWidth)
+	}
+
+	n := 0
+	for y := 0; y < g.gridHeight; y++ {
+		for x := 0; x < g.gridWidth; x++ {
+			if counts.Get(x, y) != 0 || marked[y][x] {
+				continue
+			}
+
+			n++
+			queue := []Coordinate{{x, y}}
+			marked[y][x] = true
+			for len(queue) > 0 {
+				c := queue[0]
+				queue = queue[1:]
+				if counts.Get(c.X, c.Y) != 0 {
+					continue
+				}
+
+				for x2 := c.X - 1; x2 <= c.X+1; x2++ {
+					for y2 := c.Y - 1; y2 <= c.Y+1; y2++ {
+						if g.coordinatesInBounds(x2, y2) && !marked[y2][x2] {
+							marked[y2][x2] = true
+							queue = append(queue, Coordinate{x2, y2})
+						}
+					}
+				}
+			}
+		}
+	}
+
+	for y := 0; y < g.gridHeight; y++ {
+		for x := 0; x < g.gridWidth; x++ {
+			if counts.Get(x, y) > 0 && !marked[y][x] {
+				n++
+			}
+		}
+	}
+
+	return n
+}
@@ -0,0 +1,203 @@
+package leaderboard
+
+import (
+	"github.com/jboewer/minesshweeper/game"
+	"sort"
+	"time"
+)
+
+// Result is a finished game.
+type Result struct {
+	// PlayerID is the ID of the player's profile, which identifies them
+	// across sessions and keys. Unverified games have an ID made up by
+	// their frontend.
+	PlayerID string        `json:"player_id"`
+	Name     string        `json:"name"`
+	Width    int           `json:"width"`
+	Height   int           `json:"height"`
+	Mines    int           `json:"mines"`
+	Won      bool          `json:"won"`
+	Time     time.Duration `json:"time"`
+	ThreeBV  int           `json:"3bv"`
+	Clicks   int           `json:"clicks"`
+	PlayedAt time.Time     `json:"played_at"`
+	// Daily is the date of the daily challenge the game was a ranked
+	// attempt at. It is empty for all other games.
+	Daily string `json:"daily,omitempty"`
+	// Unfinished marks the start of a ranked attempt, whose result is
+	// recorded once it ends.
+	Unfinished bool `json:"unfinished,omitempty"`
+	// Unverified marks games of players who only gave a nickname, which
+	// anyone could have played under.
+	Unverified bool `json:"unverified,omitempty"`
+}
+
+func NewResult(playerID, name string, g *game.Game) Result {
+	stats := g.Stats()
+
+	return Result{
+		PlayerID: playerID,
+		Name:     name,
+		Width:    g.GetGridWidth(),
+		Height:   g.GetGridHeight(),
+		Mines:    g.GetMineCount(),
+		Won:      g.State() == game.StateWon,
+		Time:     stats.Elapsed,
+		ThreeBV:  stats.ThreeBV,
+		Clicks:   stats.Clicks,
+		PlayedAt: time.Now(),
+	}
+}
+
+// Label is the name to show for the player, marked if it is unverified.
+func (r Result) Label() string {
+	if r.Unverified {
+		return r.Name + " (unverified)"
+	}
+	return r.Name
+}
+
+func (r Result) Difficulty() game.Difficulty {
+	return game.DifficultyOf(r.Width, r.Height, r.Mines)
+}
+
+func (r Result) ThreeBVPerSecond() float64 {
+	return game.Stats{ThreeBV: r.ThreeBV, Elapsed: r.Time}.ThreeBVPerSecond()
+}
+
+// Store keeps finished games.
+type Store interface {
+	Record(r Result) error
+	Results() ([]Result, error)
+}
+
+type Ranking int
+
+const (
+	ByTime Ranking = iota
+	ByThreeBVPerSecond
+)
+
+func (r Ranking) String() string {
+	if r == ByThreeBVPerSecond {
+		return "3BV/s"
+	}
+	return "Time"
+}
+
+// Best returns the best won game of each player on the given difficulty,
+// best first. Daily challenges have their own rankings and are left out. A
+// limit of 0 returns every player.
+func Best(results []Result, d game.Difficulty, by Ranking, limit int) []Result {
+	best := map[string]Result{}
+	for _, r := range results {
+		if !r.Won || r.Daily != "" || r.Difficulty() != d {
+			continue
+		}
+
+		if current, ok := best[r.PlayerID]; !ok || better(r, current, by) {
+			best[r.PlayerID] = r
+		}
+	}
+
+	ranked := make([]Result, 0, len(best))
+	for _, r := range best {
+		ranked = append(ranked, r)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		return better(ranked[i], ranked[j], by)
+	})
+
+	if limit > 0 && len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+
+	return ranked
+}
+
+// Boards lists the boards that have leaderboards: the presets, then the
+// extra boards given and every other board a game was recorded on, in the
+// order they were first played. Daily challenges are left out.
+func Boards(results []Result, extra ...game.Difficulty) []game.Difficulty {
+	boards := append([]game.Difficulty{}, game.Difficulties...)
+	add := func(d game.Difficulty) {
+		for _, b := range boards {
+			if b == d {
+				return
+			}
+		}
+		boards = append(boards, d)
+	}
+
+	for _, d := range extra {
+		add(d)
+	}
+	for _, r := range results {
+		if r.Daily == "" {
+			add(r.Difficulty())
+		}
+	}
+
+	return boards
+}
+
+// Record sums up a player's games on one board.
+type Record struct {
+	Difficulty game.Difficulty
+	Played     int
+	Won        int
+	// Best is the fastest win, or zero without any.
+	Best time.Duration
+}
+
+func (r Record) WinRate() float64 {
+	if r.Played == 0 {
+		return 0
+	}
+	return float64(r.Won) / float64(r.Played)
+}
+
+// RecordsOf sums up the games of a player per board, with the presets
+// first and other boards in the order they were first played. Daily
+// challenges are left out.
+func RecordsOf(results []Result, playerID string) []Record {
+	var records []Record
+	for _, d := range game.Difficulties {
+		records = append(records, Record{Difficulty: d})
+	}
+
+	for _, r := range results {
+		if r.PlayerID != playerID || r.Daily != "" {
+			continue
+		}
+
+		d := r.Difficulty()
+		i := 0
+		for i < len(records) && records[i].Difficulty != d {
+			i++
+		}
+		if i == len(records) {
+			records = append(records, Record{Difficulty: d})
+		}
+
+		records[i].Played++
+		if r.Won {
+			records[i].Won++
+			if records[i].Best == 0 || r.Time < records[i].Best {
+				records[i].Best = r.Time
+			}
+		}
+	}
+
+	return records
+}
+
+func better(a, b Result, by Ranking) bool {
+	if by == ByThreeBVPerSecond && a.ThreeBVPerSecond() != b.ThreeBVPerSecond() {
+		return a.ThreeBVPerSecond() > b.ThreeBVPerSecond()
+	}
+	if a.Time != b.Time {
+		return a.Time < b.Time
+	}
+	return a.PlayedAt.Before(b.PlayedAt)
+}
@@ -0,0 +1,144 @@
+package leaderboard_test
+
+import (
+	"github.com/jboewer/minesshweeper/game"
+	"github.com/jboewer/minesshweeper/leaderboard"
+	"github.com/stretchr/testify/assert"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func result(player string, won bool, seconds int, threeBV int) leaderboard.Result {
+	return leaderboard.Result{
+		PlayerID: player,
+		Name:     player,
+		Width:    9,
+		Height:   9,
+		Mines:    10,
+		Won:      won,
+		Time:     time.Duration(seconds) * time.Second,
+		ThreeBV:  threeBV,
+	}
+}
+
+func TestBest(t *testing.T) {
+	results := []leaderboard.Result{
+		result("alice", true, 30, 30),
+		result("alice", true, 20, 10),
+		result("bob", true, 25, 50),
+		result("carol", false, 5, 30),
+		{PlayerID: "dave", Width: 30, Height: 16, Mines: 99, Won: true, Time: time.Second},
+		{PlayerID: "erin", Width: 9, Height: 9, Mines: 10, Won: true, Time: time.Second, Daily: "2024-01-01"},
+	}
+
+	t.Run("By time", func(t *testing.T) {
+		best := leaderboard.Best(results, game.Beginner, leaderboard.ByTime, 0)
+
+		assert.Len(t, best, 2)
+		assert.Equal(t, "alice", best[0].PlayerID)
+		assert.Equal(t, 20*time.Second, best[0].Time)
+		assert.Equal(t, "bob", best[1].PlayerID)
+	})
+	t.Run("By 3BV/s", func(t *testing.T) {
+		best := leaderboard.Best(results, game.Beginner, leaderboard.ByThreeBVPerSecond, 0)
+
+		assert.Len(t, best, 2)
+		assert.Equal(t, "bob", best[0].PlayerID)
+		assert.Equal(t, "alice", best[1].PlayerID)
+		assert.Equal(t, 30*time.Second, best[1].Time)
+	})
+	t.Run("Limit", func(t *testing.T) {
+		assert.Len(t, leaderboard.Best(results, game.Beginner, leaderboard.ByTime, 1), 1)
+	})
+}
+
+func TestBoards(t *testing.T) {
+	results := []leaderboard.Result{
+		result("alice", true, 30, 30),
+		{PlayerID: "alice", Width: 20, Height: 20, Mines: 50},
+		{PlayerID: "bob", Width: 10, Height: 10, Mines: 10, Won: true},
+		{PlayerID: "bob", Width: 16, Height: 16, Mines: 40, Daily: "2024-01-01"},
+	}
+
+	boards := leaderboard.Boards(results, game.DifficultyOf(10, 10, 10), game.Expert)
+
+	assert.Equal(t, []game.Difficulty{
+		game.Beginner,
+		game.Intermediate,
+		game.Expert,
+		game.DifficultyOf(10, 10, 10),
+		game.DifficultyOf(20, 20, 50),
+	}, boards)
+}
+
+func TestRecordsOf(t *testing.T) {
+	results := []leaderboard.Result{
+		result("alice", true, 30, 30),
+		result("alice", false, 5, 30),
+		result("alice", true, 20, 10),
+		result("bob", true, 10, 10),
+		{PlayerID: "alice", Width: 10, Height: 10, Mines: 10, Won: false},
+		{PlayerID: "alice", Width: 9, Height: 9, Mines: 10, Won: true, Time: time.Second, Daily: "2024-01-01"},
+	}
+
+	records := leaderboard.RecordsOf(results, "alice")
+
+	assert.Len(t, records, 4)
+	assert.Equal(t, leaderboard.Record{Difficulty: game.Beginner, Played: 3, Won: 2, Best: 20 * time.Second}, records[0])
+	assert.InDelta(t, 2.0/3, records[0].WinRate(), 0.001)
+	assert.Equal(t, game.Expert, records[2].Difficulty)
+	assert.Equal(t, 0.0, records[2].WinRate())
+	assert.Equal(t, "Custom 10x10/10", records[3].Difficulty.Name)
+	assert.Equal(t, 1, records[3].Played)
+}
+
+func TestMemoryStore(t *testing.T) {
+	s := leaderboard.NewMemoryStore()
+
+	assert.NoError(t, s.Record(result("alice", true, 10, 10)))
+
+	results, err := s.Results()
+	assert.NoError(t, err)
+	assert.Equal(t, []leaderboard.Result{result("alice", true, 10, 10)}, results)
+}
+
+func TestFileStore_PersistsResults(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data", "results.jsonl")
+
+	s, err := leaderboard.OpenFileStore(path)
+	assert.NoError(t, err)
+	assert.NoError(t, s.Record(result("alice", true, 10, 10)))
+	assert.NoError(t, s.Record(result("bob", false, 5, 20)))
+
+	reopened, err := leaderboard.OpenFileStore(path)
+	assert.NoError(t, err)
+
+	results, err := reopened.Results()
+	assert.NoError(t, err)
+	assert.Equal(t, []leaderboard.Result{
+		result("alice", true, 10, 10),
+		result("bob", false, 5, 20),
+	}, results)
+}
+
+func TestFileStore_SharedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results.jsonl")
+
+	a, err := leaderboard.OpenFileStore(path)
+	assert.NoError(t, err)
+	b, err := leaderboard.OpenFileStore(path)
+	assert.NoError(t, err)
+
+	assert.NoError(t, a.Record(result("alice", true, 10, 10)))
+	assert.NoError(t, b.Record(result("bob", true, 20, 10)))
+
+	for _, s := range []*leaderboard.FileStore{a, b} {
+		results, err := s.Results()
+		assert.NoError(t, err)
+		assert.Equal(t, []leaderboard.Result{
+			result("alice", true, 10, 10),
+			result("bob", true, 20, 10),
+		}, results)
+	}
+}
@@ -0,0 +1,137 @@
+package leaderboard
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+type MemoryStore struct {
+	mu      sync.Mutex
+	results []Result
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+func (s *MemoryStore) Record(r Result) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.results = append(s.results, r)
+	return nil
+}
+
+func (s *MemoryStore) Results() ([]Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Result(nil), s.results...), nil
+}
+
+// FileStore keeps results in a file with one JSON object per line. Results
+// are appended to the file on every Record. Lines appended by other
+// processes sharing the file are picked up by the next call to Results.
+type FileStore struct {
+	path   string
+	memory MemoryStore
+	// offset is how far the file has been read.
+	offset int64
+}
+
+func OpenFileStore(path string) (*FileStore, error) {
+	s := &FileStore{path: path}
+
+	s.memory.mu.Lock()
+	defer s.memory.mu.Unlock()
+
+	if err := s.refresh(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// refresh reads the complete lines that were added to the file since it
+// was last read.
+func (s *FileStore) refresh() error {
+	f, err := os.Open(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if _, err := f.Seek(s.offset, io.SeekStart); err != nil {
+		return err
+	}
+
+	reader := bufio.NewReader(f)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			// A line without a newline is still being written.
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		s.offset += int64(len(line))
+
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+
+		var r Result
+		if err := json.Unmarshal(line, &r); err != nil {
+			return err
+		}
+		s.memory.results = append(s.memory.results, r)
+	}
+}
+
+func (s *FileStore) Record(r Result) error {
+	s.memory.mu.Lock()
+	defer s.memory.mu.Unlock()
+
+	line, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return err
+	}
+
+	return s.refresh()
+}
+
+func (s *FileStore) Results() ([]Result, error) {
+	s.memory.mu.Lock()
+	err := s.refresh()
+	s.memory.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	return s.memory.Results()
+}
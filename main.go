@@ -1,25 +1,49 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/jboewer/minesshweeper/game"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jboewer/minesshweeper/leaderboard"
 	"github.com/jboewer/minesshweeper/tui"
 	"os"
 )
 
 func main() {
-	g, err := game.New(10, 10)
-	if err != nil {
-		fmt.Printf("Alas, there's been an error: %v", err)
+	keys := flag.String("keys", "default", "key map to play with: default, vim, wasd, arrows, azerty or one from -keymaps")
+	keyMapsPath := flag.String("keymaps", "", "path of a YAML file with custom key maps")
+	flag.Parse()
+
+	keyMaps := tui.KeyMaps
+	if *keyMapsPath != "" {
+		custom, err := tui.LoadKeyMaps(*keyMapsPath)
+		if err != nil {
+			fmt.Printf("Could not load key maps: %v\n", err)
+			os.Exit(1)
+		}
+		keyMaps = append(keyMaps, custom...)
+	}
+
+	settings := tui.DefaultSettings(lipgloss.DefaultRenderer())
+	k, ok := tui.FindKeyMap(keyMaps, *keys)
+	if !ok {
+		fmt.Printf("Unknown key map %s\n", *keys)
 		os.Exit(1)
 	}
+	settings.Keys = k
 
-	g.PlaceRandomMines(10)
+	// Stats are kept for as long as the game runs.
+	services := &tui.Services{
+		Results: leaderboard.NewMemoryStore(),
+		KeyMaps: keyMaps,
+	}
+	player := tui.Player{ProfileID: "local", Name: "local"}
 
 	p := tea.NewProgram(
-		tui.NewGameModel(g),
+		tui.NewMenuModel(services, player, settings),
 		tea.WithAltScreen(),
+		tea.WithMouseAllMotion(),
 	)
 	if _, err := p.Run(); err != nil {
 		fmt.Printf("Alas, there's been an error: %v", err)
@@ -0,0 +1,124 @@
+package profile
+
+import (
+	"crypto/rand"
+	"errors"
+	"sync"
+	"time"
+)
+
+var (
+	ErrInvalidLinkCode  = errors.New("unknown or expired link code")
+	ErrTooManyLinkCodes = errors.New("too many wrong link codes, try again later")
+)
+
+const (
+	// LinkCodeLength makes link codes too many to guess. Every character
+	// is one of 32, so a code is one of 32^10.
+	LinkCodeLength   = 10
+	linkCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	linkCodeLifetime = 10 * time.Minute
+	// maxFailedRedeems is how many wrong codes a client may try in the
+	// lifetime of a code.
+	maxFailedRedeems = 5
+)
+
+// LinkCodes hands out short-lived codes that let a player prove, from a
+// session with a new key, that they own an existing profile.
+type LinkCodes struct {
+	mu       sync.Mutex
+	codes    map[string]linkCode
+	failures map[string]linkFailures
+	now      func() time.Time
+}
+
+type linkCode struct {
+	profileID string
+	expires   time.Time
+}
+
+// linkFailures counts the wrong codes a client tried since the given time.
+type linkFailures struct {
+	count int
+	since time.Time
+}
+
+func NewLinkCodes() *LinkCodes {
+	return &LinkCodes{
+		codes:    map[string]linkCode{},
+		failures: map[string]linkFailures{},
+		now:      time.Now,
+	}
+}
+
+func (l *LinkCodes) Issue(profileID string) string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for code, c := range l.codes {
+		if l.now().After(c.expires) {
+			delete(l.codes, code)
+		}
+	}
+	for client, f := range l.failures {
+		if l.now().Sub(f.since) > linkCodeLifetime {
+			delete(l.failures, client)
+		}
+	}
+
+	var code string
+	for {
+		code = newLinkCode()
+		if _, taken := l.codes[code]; !taken {
+			break
+		}
+	}
+	l.codes[code] = linkCode{
+		profileID: profileID,
+		expires:   l.now().Add(linkCodeLifetime),
+	}
+
+	return code
+}
+
+// newLinkCode returns a random code from a cryptographic source, since a
+// code that can be guessed lets anyone take over a profile.
+func newLinkCode() string {
+	b := make([]byte, LinkCodeLength)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	for i := range b {
+		// The alphabet has 32 characters, which divide 256 evenly.
+		b[i] = linkCodeAlphabet[int(b[i])%len(linkCodeAlphabet)]
+	}
+	return string(b)
+}
+
+// Redeem returns the profile a code was issued for. Every code can only be
+// used once. client tells apart who redeems codes, such as by the address
+// they connect from, which is harder to change than a key. A client that
+// tried too many wrong codes has to wait until the codes it could have
+// guessed have expired.
+func (l *LinkCodes) Redeem(code, client string) (string, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	f := l.failures[client]
+	if l.now().Sub(f.since) > linkCodeLifetime {
+		f = linkFailures{since: l.now()}
+	}
+	if f.count >= maxFailedRedeems {
+		return "", ErrTooManyLinkCodes
+	}
+
+	c, ok := l.codes[code]
+	delete(l.codes, code)
+	if !ok || l.now().After(c.expires) {
+		f.count++
+		l.failures[client] = f
+		return "", ErrInvalidLinkCode
+	}
+
+	return c.profileID, nil
+}
@@ -0,0 +1,96 @@
+package profile
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNotFound    = errors.New("profile not found")
+	ErrNameTaken   = errors.New("nickname is already taken")
+	ErrInvalidName = errors.New("nicknames are 2 to 16 letters, digits, - or _")
+	ErrKeyInUse    = errors.New("key already belongs to a profile")
+)
+
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9_-]{2,16}$`)
+
+// Profile is a player known to the server. A profile can be reached through
+// any of its SSH keys.
+type Profile struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Keys are the fingerprints of the player's SSH public keys.
+	Keys        []string  `json:"keys"`
+	Difficulty  string    `json:"difficulty,omitempty"`
+	Theme       string    `json:"theme,omitempty"`
+	KeyBindings string    `json:"key_bindings,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func New(name, keyID string) (Profile, error) {
+	if err := ValidateName(name); err != nil {
+		return Profile{}, err
+	}
+
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return Profile{}, err
+	}
+
+	return Profile{
+		ID:        hex.EncodeToString(id),
+		Name:      name,
+		Keys:      []string{keyID},
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+func ValidateName(name string) error {
+	if !namePattern.MatchString(name) {
+		return ErrInvalidName
+	}
+	return nil
+}
+
+func (p Profile) HasKey(keyID string) bool {
+	for _, k := range p.Keys {
+		if k == keyID {
+			return true
+		}
+	}
+	return false
+}
+
+// Store keeps profiles. Implementations must reject a Save that would give a
+// nickname or a key to more than one profile.
+type Store interface {
+	Get(id string) (Profile, error)
+	ByKey(keyID string) (Profile, error)
+	Save(p Profile) error
+}
+
+// LinkKey adds keyID to the profile with the given ID.
+func LinkKey(s Store, id, keyID string) (Profile, error) {
+	if _, err := s.ByKey(keyID); err == nil {
+		return Profile{}, ErrKeyInUse
+	} else if !errors.Is(err, ErrNotFound) {
+		return Profile{}, err
+	}
+
+	p, err := s.Get(id)
+	if err != nil {
+		return Profile{}, err
+	}
+
+	p.Keys = append(p.Keys, keyID)
+	return p, s.Save(p)
+}
+
+// sameName compares nicknames the way players read them.
+func sameName(a, b string) bool {
+	return strings.EqualFold(a, b)
+}
@@ -0,0 +1,123 @@
+package profile_test
+
+import (
+	"github.com/jboewer/minesshweeper/profile"
+	"github.com/stretchr/testify/assert"
+	"path/filepath"
+	"testing"
+)
+
+func newProfile(t *testing.T, s profile.Store, name, keyID string) profile.Profile {
+	t.Helper()
+
+	p, err := profile.New(name, keyID)
+	assert.NoError(t, err)
+	assert.NoError(t, s.Save(p))
+
+	return p
+}
+
+func TestNew_ValidatesName(t *testing.T) {
+	for _, name := range []string{"", "a", "much-too-long-nickname", "no spaces", "ünicode"} {
+		_, err := profile.New(name, "key")
+		assert.ErrorIs(t, err, profile.ErrInvalidName, name)
+	}
+
+	p, err := profile.New("alice_1", "key")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, p.ID)
+	assert.Equal(t, []string{"key"}, p.Keys)
+}
+
+func TestMemoryStore(t *testing.T) {
+	s := profile.NewMemoryStore()
+	alice := newProfile(t, s, "alice", "key-a")
+
+	t.Run("Lookup", func(t *testing.T) {
+		found, err := s.ByKey("key-a")
+		assert.NoError(t, err)
+		assert.Equal(t, alice.ID, found.ID)
+
+		_, err = s.ByKey("key-x")
+		assert.ErrorIs(t, err, profile.ErrNotFound)
+		_, err = s.Get("nope")
+		assert.ErrorIs(t, err, profile.ErrNotFound)
+	})
+	t.Run("Names are unique", func(t *testing.T) {
+		bob, _ := profile.New("ALICE", "key-b")
+		assert.ErrorIs(t, s.Save(bob), profile.ErrNameTaken)
+	})
+	t.Run("Keys are unique", func(t *testing.T) {
+		bob, _ := profile.New("bob", "key-a")
+		assert.ErrorIs(t, s.Save(bob), profile.ErrKeyInUse)
+	})
+	t.Run("Rename", func(t *testing.T) {
+		alice.Name = "Alicia"
+		assert.NoError(t, s.Save(alice))
+
+		found, _ := s.Get(alice.ID)
+		assert.Equal(t, "Alicia", found.Name)
+	})
+}
+
+func TestLinkKey(t *testing.T) {
+	s := profile.NewMemoryStore()
+	alice := newProfile(t, s, "alice", "key-a")
+	newProfile(t, s, "bob", "key-b")
+
+	linked, err := profile.LinkKey(s, alice.ID, "key-c")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"key-a", "key-c"}, linked.Keys)
+
+	found, _ := s.ByKey("key-c")
+	assert.Equal(t, alice.ID, found.ID)
+
+	_, err = profile.LinkKey(s, alice.ID, "key-b")
+	assert.ErrorIs(t, err, profile.ErrKeyInUse)
+}
+
+func TestLinkCodes(t *testing.T) {
+	t.Run("Codes work once", func(t *testing.T) {
+		codes := profile.NewLinkCodes()
+
+		code := codes.Issue("alice")
+		assert.Len(t, code, profile.LinkCodeLength)
+		id, err := codes.Redeem(code, "10.0.0.2")
+		assert.NoError(t, err)
+		assert.Equal(t, "alice", id)
+
+		_, err = codes.Redeem(code, "10.0.0.2")
+		assert.ErrorIs(t, err, profile.ErrInvalidLinkCode)
+	})
+	t.Run("Too many wrong codes", func(t *testing.T) {
+		codes := profile.NewLinkCodes()
+		code := codes.Issue("alice")
+
+		for i := 0; i < 5; i++ {
+			_, err := codes.Redeem("WRONG", "10.0.0.66")
+			assert.ErrorIs(t, err, profile.ErrInvalidLinkCode)
+		}
+		_, err := codes.Redeem(code, "10.0.0.66")
+		assert.ErrorIs(t, err, profile.ErrTooManyLinkCodes)
+
+		id, err := codes.Redeem(code, "10.0.0.2")
+		assert.NoError(t, err)
+		assert.Equal(t, "alice", id)
+	})
+}
+
+func TestFileStore_PersistsProfiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data", "profiles.json")
+
+	s, err := profile.OpenFileStore(path)
+	assert.NoError(t, err)
+	alice := newProfile(t, s, "alice", "key-a")
+
+	reopened, err := profile.OpenFileStore(path)
+	assert.NoError(t, err)
+
+	found, err := reopened.ByKey("key-a")
+	assert.NoError(t, err)
+	assert.Equal(t, alice.ID, found.ID)
+	assert.Equal(t, "alice", found.Name)
+}
@@ -0,0 +1,160 @@
+package profile
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+type MemoryStore struct {
+	mu       sync.Mutex
+	profiles map[string]Profile
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		profiles: map[string]Profile{},
+	}
+}
+
+func (s *MemoryStore) Get(id string) (Profile, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.profiles[id]
+	if !ok {
+		return Profile{}, ErrNotFound
+	}
+	return p, nil
+}
+
+func (s *MemoryStore) ByKey(keyID string) (Profile, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, p := range s.profiles {
+		if p.HasKey(keyID) {
+			return p, nil
+		}
+	}
+	return Profile{}, ErrNotFound
+}
+
+func (s *MemoryStore) Save(p Profile) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.save(p)
+}
+
+func (s *MemoryStore) save(p Profile) error {
+	if err := ValidateName(p.Name); err != nil {
+		return err
+	}
+
+	for id, other := range s.profiles {
+		if id == p.ID {
+			continue
+		}
+		if sameName(other.Name, p.Name) {
+			return ErrNameTaken
+		}
+		for _, k := range p.Keys {
+			if other.HasKey(k) {
+				return ErrKeyInUse
+			}
+		}
+	}
+
+	p.Keys = append([]string(nil), p.Keys...)
+	s.profiles[p.ID] = p
+	return nil
+}
+
+// FileStore keeps all profiles in a single JSON file, which is rewritten on
+// every Save.
+type FileStore struct {
+	path   string
+	memory *MemoryStore
+}
+
+func OpenFileStore(path string) (*FileStore, error) {
+	s := &FileStore{
+		path:   path,
+		memory: NewMemoryStore(),
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var profiles []Profile
+	if err := json.Unmarshal(data, &profiles); err != nil {
+		return nil, err
+	}
+	for _, p := range profiles {
+		s.memory.profiles[p.ID] = p
+	}
+
+	return s, nil
+}
+
+func (s *FileStore) Get(id string) (Profile, error) {
+	return s.memory.Get(id)
+}
+
+func (s *FileStore) ByKey(keyID string) (Profile, error) {
+	return s.memory.ByKey(keyID)
+}
+
+func (s *FileStore) Save(p Profile) error {
+	s.memory.mu.Lock()
+	defer s.memory.mu.Unlock()
+
+	previous, existed := s.memory.profiles[p.ID]
+	if err := s.memory.save(p); err != nil {
+		return err
+	}
+
+	if err := s.write(); err != nil {
+		if existed {
+			s.memory.profiles[p.ID] = previous
+		} else {
+			delete(s.memory.profiles, p.ID)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// write replaces the file through a temporary file, so that a crash never
+// leaves half of it behind.
+func (s *FileStore) write() error {
+	profiles := make([]Profile, 0, len(s.memory.profiles))
+	for _, p := range s.memory.profiles {
+		profiles = append(profiles, p)
+	}
+
+	data, err := json.MarshalIndent(profiles, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return err
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, s.path)
+}
@@ -0,0 +1,342 @@
+package room
+
+import (
+	"errors"
+	"github.com/jboewer/minesshweeper/game"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var (
+	ErrRoomNotFound = errors.New("room not found")
+	ErrNotInRoom    = errors.New("player is not in this room")
+)
+
+const (
+	codeLength   = 4
+	codeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+)
+
+// Colors handed out to players in the order they join a room.
+var playerColors = []string{
+	"#33CCFF",
+	"#FF9933",
+	"#66FF66",
+	"#FFFF33",
+	"#FF6666",
+	"#CC99FF",
+	"#33FFCC",
+	"#FF66CC",
+}
+
+type Player struct {
+	ID     string
+	Name   string
+	Color  string
+	Cursor game.Coordinate
+}
+
+type member struct {
+	player  Player
+	updates chan struct{}
+}
+
+// Room is a board shared by several players. Every change made through the
+// room is announced to the other members on their update channel.
+type Room struct {
+	Code string
+	Game *game.Game
+
+	mu        sync.Mutex
+	members   map[string]*member
+	nextColor int
+}
+
+func (r *Room) Players() []Player {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	players := make([]Player, 0, len(r.members))
+	for _, m := range r.members {
+		players = append(players, m.player)
+	}
+	sort.Slice(players, func(i, j int) bool {
+		return players[i].ID < players[j].ID
+	})
+
+	return players
+}
+
+func (r *Room) MoveCursor(playerID string, x, y int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	m, ok := r.members[playerID]
+	if !ok {
+		return ErrNotInRoom
+	}
+
+	m.player.Cursor = game.Coordinate{X: x, Y: y}
+	r.broadcast(playerID)
+
+	return nil
+}
+
+func (r *Room) Reveal(playerID string, x, y int) (game.RevealResult, error) {
+	if !r.isMember(playerID) {
+		return game.RevealResult{}, ErrNotInRoom
+	}
+
+	res, err := r.Game.Reveal(x, y)
+	if err != nil {
+		return res, err
+	}
+
+	r.Broadcast(playerID)
+	return res, nil
+}
+
+func (r *Room) Chord(playerID string, x, y int) (game.RevealResult, error) {
+	if !r.isMember(playerID) {
+		return game.RevealResult{}, ErrNotInRoom
+	}
+
+	res, err := r.Game.Chord(x, y)
+	if err != nil {
+		return res, err
+	}
+
+	r.Broadcast(playerID)
+	return res, nil
+}
+
+func (r *Room) ToggleFlag(playerID string, x, y int) error {
+	if !r.isMember(playerID) {
+		return ErrNotInRoom
+	}
+
+	r.Game.ToggleFlag(x, y)
+	r.Broadcast(playerID)
+
+	return nil
+}
+
+func (r *Room) Reset(playerID string) error {
+	if !r.isMember(playerID) {
+		return ErrNotInRoom
+	}
+
+	r.Game.Reset()
+	r.Broadcast(playerID)
+
+	return nil
+}
+
+// Restart starts the room over on the same board.
+func (r *Room) Restart(playerID string) error {
+	if !r.isMember(playerID) {
+		return ErrNotInRoom
+	}
+
+	r.Game.Restart()
+	r.Broadcast(playerID)
+
+	return nil
+}
+
+// Reseed starts the room over on the board laid out from seed.
+func (r *Room) Reseed(playerID string, seed int64) error {
+	if !r.isMember(playerID) {
+		return ErrNotInRoom
+	}
+
+	r.Game.Reseed(seed)
+	r.Broadcast(playerID)
+
+	return nil
+}
+
+// Broadcast notifies every member except the given one that the room has
+// changed.
+func (r *Room) Broadcast(exceptID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.broadcast(exceptID)
+}
+
+func (r *Room) broadcast(exceptID string) {
+	for id, m := range r.members {
+		if id == exceptID {
+			continue
+		}
+
+		// Updates only tell the receiver to re-render, so a pending one
+		// already covers this change.
+		select {
+		case m.updates <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (r *Room) isMember(playerID string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, ok := r.members[playerID]
+	return ok
+}
+
+func (r *Room) join(playerID, name string) (Player, <-chan struct{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	m := &member{
+		player: Player{
+			ID:    playerID,
+			Name:  name,
+			Color: playerColors[r.nextColor%len(playerColors)],
+		},
+		updates: make(chan struct{}, 1),
+	}
+	r.nextColor++
+	r.members[playerID] = m
+	r.broadcast(playerID)
+
+	return m.player, m.updates
+}
+
+// leave removes the player and reports whether the room is now empty.
+func (r *Room) leave(playerID string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if m, ok := r.members[playerID]; ok {
+		close(m.updates)
+		delete(r.members, playerID)
+		r.broadcast(playerID)
+	}
+
+	return len(r.members) == 0
+}
+
+// Registry keeps track of the open rooms. Each player can be in at most one
+// room at a time; rooms are closed when their last player leaves.
+type Registry struct {
+	mu      sync.Mutex
+	rooms   map[string]*Room
+	players map[string]*Room
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		rooms:   map[string]*Room{},
+		players: map[string]*Room{},
+	}
+}
+
+// Create opens a new room for g and joins the player to it.
+func (reg *Registry) Create(g *game.Game, playerID, name string) (*Room, Player, <-chan struct{}) {
+	reg.Leave(playerID)
+
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	r := &Room{
+		Code:    reg.newCode(),
+		Game:    g,
+		members: map[string]*member{},
+	}
+	reg.rooms[r.Code] = r
+
+	p, updates := reg.join(r, playerID, name)
+	return r, p, updates
+}
+
+// Join adds the player to the room with the given code, leaving any room
+// they were in before.
+func (reg *Registry) Join(code, playerID, name string) (*Room, Player, <-chan struct{}, error) {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	r, ok := reg.rooms[strings.ToUpper(strings.TrimSpace(code))]
+	if !ok {
+		return nil, Player{}, nil, ErrRoomNotFound
+	}
+
+	if reg.players[playerID] == r {
+		// Rejoining the same room mustn't close it on the way.
+		r.leave(playerID)
+	} else {
+		reg.leave(playerID)
+	}
+
+	p, updates := reg.join(r, playerID, name)
+	return r, p, updates, nil
+}
+
+// Leave removes the player from whatever room they are in. It is safe to
+// call for players that are not in a room.
+func (reg *Registry) Leave(playerID string) {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	reg.leave(playerID)
+}
+
+func (reg *Registry) leave(playerID string) {
+	r, ok := reg.players[playerID]
+	if !ok {
+		return
+	}
+
+	delete(reg.players, playerID)
+	if r.leave(playerID) {
+		delete(reg.rooms, r.Code)
+	}
+}
+
+func (reg *Registry) Rooms() []*Room {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	rooms := make([]*Room, 0, len(reg.rooms))
+	for _, r := range reg.rooms {
+		rooms = append(rooms, r)
+	}
+	sort.Slice(rooms, func(i, j int) bool {
+		return rooms[i].Code < rooms[j].Code
+	})
+
+	return rooms
+}
+
+func (reg *Registry) join(r *Room, playerID, name string) (Player, <-chan struct{}) {
+	reg.players[playerID] = r
+	return r.join(playerID, name)
+}
+
+func (reg *Registry) newCode() string {
+	return NewCode(func(code string) bool {
+		_, taken := reg.rooms[code]
+		return taken
+	})
+}
+
+// NewCode returns a short random code that players can type to find a
+// session. taken reports whether a code is already in use.
+func NewCode(taken func(code string) bool) string {
+	for {
+		b := make([]byte, codeLength)
+		for i := range b {
+			b[i] = codeAlphabet[rand.Intn(len(codeAlphabet))]
+		}
+
+		if !taken(string(b)) {
+			return string(b)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package room_test
+
+import (
+	"github.com/jboewer/minesshweeper/game"
+	"github.com/jboewer/minesshweeper/room"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func newGame(t *testing.T) *game.Game {
+	t.Helper()
+
+	g, err := game.NewFromGrid(game.Grid{
+		{1, 0, 0},
+		{0, 0, 0},
+	})
+	assert.NoError(t, err)
+
+	return g
+}
+
+func assertUpdated(t *testing.T, updates <-chan struct{}) {
+	t.Helper()
+
+	select {
+	case <-updates:
+	default:
+		t.Error("Expected an update")
+	}
+}
+
+func assertNotUpdated(t *testing.T, updates <-chan struct{}) {
+	t.Helper()
+
+	select {
+	case <-updates:
+		t.Error("Expected no update")
+	default:
+	}
+}
+
+func TestRegistry_CreateAndJoin(t *testing.T) {
+	reg := room.NewRegistry()
+
+	r, alice, _ := reg.Create(newGame(t), "a", "alice")
+	joined, bob, _, err := reg.Join(r.Code, "b", "bob")
+
+	assert.NoError(t, err)
+	assert.Same(t, r, joined)
+	assert.Len(t, r.Code, 4)
+	assert.NotEqual(t, alice.Color, bob.Color)
+	assert.Equal(t, []room.Player{alice, bob}, r.Players())
+}
+
+func TestRegistry_JoinIsCaseInsensitive(t *testing.T) {
+	reg := room.NewRegistry()
+
+	r, _, _ := reg.Create(newGame(t), "a", "alice")
+	_, _, _, err := reg.Join(" "+strings.ToLower(r.Code)+" ", "b", "bob")
+	assert.NoError(t, err)
+}
+
+func TestRegistry_JoinUnknownRoom(t *testing.T) {
+	reg := room.NewRegistry()
+
+	_, _, _, err := reg.Join("NOPE", "a", "alice")
+	assert.ErrorIs(t, err, room.ErrRoomNotFound)
+}
+
+func TestRegistry_RejoinTheSameRoom(t *testing.T) {
+	reg := room.NewRegistry()
+
+	r, _, before := reg.Create(newGame(t), "a", "alice")
+	joined, alice, _, err := reg.Join(r.Code, "a", "alice")
+
+	assert.NoError(t, err)
+	assert.Same(t, r, joined)
+	assert.Equal(t, []room.Player{alice}, r.Players())
+	assert.Equal(t, []*room.Room{r}, reg.Rooms())
+
+	_, open := <-before
+	assert.False(t, open)
+}
+
+func TestRegistry_RoomClosesWhenEmpty(t *testing.T) {
+	reg := room.NewRegistry()
+
+	r, _, updates := reg.Create(newGame(t), "a", "alice")
+	reg.Leave("a")
+
+	_, open := <-updates
+	assert.False(t, open)
+	assert.Empty(t, reg.Rooms())
+
+	_, _, _, err := reg.Join(r.Code, "b", "bob")
+	assert.ErrorIs(t, err, room.ErrRoomNotFound)
+}
+
+func TestRoom_BroadcastsToOtherPlayers(t *testing.T) {
+	reg := room.NewRegistry()
+
+	r, _, aliceUpdates := reg.Create(newGame(t), "a", "alice")
+	_, _, bobUpdates, _ := reg.Join(r.Code, "b", "bob")
+	assertUpdated(t, aliceUpdates)
+
+	res, err := r.Reveal("b", 2, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, game.RevealSafe, res.Outcome)
+	assertUpdated(t, aliceUpdates)
+	assertNotUpdated(t, bobUpdates)
+
+	assert.NoError(t, r.ToggleFlag("a", 0, 0))
+	assertUpdated(t, bobUpdates)
+	assertNotUpdated(t, aliceUpdates)
+
+	assert.NoError(t, r.MoveCursor("a", 1, 1))
+	assertUpdated(t, bobUpdates)
+	assert.Equal(t, game.Coordinate{X: 1, Y: 1}, r.Players()[0].Cursor)
+}
+
+func TestRoom_RejectsPlayersOutsideTheRoom(t *testing.T) {
+	reg := room.NewRegistry()
+
+	r, _, _ := reg.Create(newGame(t), "a", "alice")
+
+	_, err := r.Reveal("b", 0, 0)
+	assert.ErrorIs(t, err, room.ErrNotInRoom)
+	_, err = r.Chord("b", 0, 0)
+	assert.ErrorIs(t, err, room.ErrNotInRoom)
+	assert.ErrorIs(t, r.ToggleFlag("b", 0, 0), room.ErrNotInRoom)
+	assert.ErrorIs(t, r.MoveCursor("b", 0, 0), room.ErrNotInRoom)
+}
@@ -0,0 +1,209 @@
+package spectate
+
+import (
+	"errors"
+	"github.com/jboewer/minesshweeper/game"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	ErrSessionNotFound = errors.New("session not found")
+)
+
+// Session is a game that is being played on the server. Spectators watch it
+// through the update channels handed out by Watch.
+type Session struct {
+	ID        string
+	Player    string
+	Game      *game.Game
+	StartedAt time.Time
+
+	mu       sync.Mutex
+	cursor   game.Coordinate
+	watchers map[int]watcher
+	nextID   int
+	closed   bool
+}
+
+// watcher is a spectator's channel, together with the ID of the spectator's
+// own session.
+type watcher struct {
+	spectator string
+	updates   chan struct{}
+}
+
+func (s *Session) Cursor() game.Coordinate {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.cursor
+}
+
+func (s *Session) MoveCursor(x, y int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.cursor = game.Coordinate{X: x, Y: y}
+	s.notify()
+}
+
+// Changed tells the spectators that the board has changed.
+func (s *Session) Changed() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.notify()
+}
+
+// Watch returns a channel that receives a value whenever the session
+// changes, and a function to stop watching. The channel is closed when the
+// session ends, or when the spectator's session does.
+func (s *Session) Watch(spectator string) (<-chan struct{}, func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	updates := make(chan struct{}, 1)
+	if s.closed {
+		close(updates)
+		return updates, func() {}
+	}
+
+	id := s.nextID
+	s.nextID++
+	s.watchers[id] = watcher{spectator: spectator, updates: updates}
+
+	return updates, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if w, ok := s.watchers[id]; ok {
+			close(w.updates)
+			delete(s.watchers, id)
+		}
+	}
+}
+
+func (s *Session) unwatch(spectator string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, w := range s.watchers {
+		if w.spectator == spectator {
+			close(w.updates)
+			delete(s.watchers, id)
+		}
+	}
+}
+
+func (s *Session) Watchers() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.watchers)
+}
+
+func (s *Session) notify() {
+	for _, w := range s.watchers {
+		select {
+		case w.updates <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (s *Session) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, w := range s.watchers {
+		close(w.updates)
+		delete(s.watchers, id)
+	}
+	s.closed = true
+}
+
+// Registry lists the games currently being played, keyed by the ID of the
+// session playing them.
+type Registry struct {
+	mu       sync.Mutex
+	sessions map[string]*Session
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		sessions: map[string]*Session{},
+	}
+}
+
+// Publish makes g watchable. A session that publishes a new game replaces
+// its previous one, which ends it for its spectators.
+func (r *Registry) Publish(id, player string, g *game.Game) *Session {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if old, ok := r.sessions[id]; ok {
+		old.close()
+	}
+
+	s := &Session{
+		ID:        id,
+		Player:    player,
+		Game:      g,
+		StartedAt: time.Now(),
+		watchers:  map[int]watcher{},
+	}
+	r.sessions[id] = s
+
+	return s
+}
+
+func (r *Registry) Remove(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if s, ok := r.sessions[id]; ok {
+		s.close()
+		delete(r.sessions, id)
+	}
+}
+
+// Unwatch stops everything the spectator with the given session ID is
+// watching.
+func (r *Registry) Unwatch(spectator string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, s := range r.sessions {
+		s.unwatch(spectator)
+	}
+}
+
+func (r *Registry) Get(id string) (*Session, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s, ok := r.sessions[id]
+	if !ok {
+		return nil, ErrSessionNotFound
+	}
+
+	return s, nil
+}
+
+// List returns the active sessions, oldest first.
+func (r *Registry) List() []*Session {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	sessions := make([]*Session, 0, len(r.sessions))
+	for _, s := range r.sessions {
+		sessions = append(sessions, s)
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].StartedAt.Before(sessions[j].StartedAt)
+	})
+
+	return sessions
+}
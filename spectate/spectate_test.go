@@ -0,0 +1,80 @@
+package spectate_test
+
+import (
+	"github.com/jboewer/minesshweeper/game"
+	"github.com/jboewer/minesshweeper/spectate"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestRegistry_PublishAndList(t *testing.T) {
+	reg := spectate.NewRegistry()
+	g, _ := game.New(5, 5)
+
+	s := reg.Publish("a", "alice", g)
+	reg.Publish("b", "bob", g)
+
+	found, err := reg.Get("a")
+	assert.NoError(t, err)
+	assert.Same(t, s, found)
+	assert.Len(t, reg.List(), 2)
+	assert.Equal(t, "alice", reg.List()[0].Player)
+
+	reg.Remove("a")
+	_, err = reg.Get("a")
+	assert.ErrorIs(t, err, spectate.ErrSessionNotFound)
+}
+
+func TestSession_WatchersAreNotified(t *testing.T) {
+	reg := spectate.NewRegistry()
+	g, _ := game.New(5, 5)
+	s := reg.Publish("a", "alice", g)
+
+	updates, stop := s.Watch("b")
+	assert.Equal(t, 1, s.Watchers())
+
+	s.MoveCursor(2, 3)
+	_, ok := <-updates
+	assert.True(t, ok)
+	assert.Equal(t, game.Coordinate{X: 2, Y: 3}, s.Cursor())
+
+	stop()
+	_, ok = <-updates
+	assert.False(t, ok)
+	assert.Zero(t, s.Watchers())
+}
+
+func TestSession_WatchEndsWhenSessionIsReplaced(t *testing.T) {
+	reg := spectate.NewRegistry()
+	g, _ := game.New(5, 5)
+	s := reg.Publish("a", "alice", g)
+
+	updates, stop := s.Watch("b")
+	reg.Publish("a", "alice", g)
+
+	_, ok := <-updates
+	assert.False(t, ok)
+	stop()
+
+	updates, _ = s.Watch("b")
+	_, ok = <-updates
+	assert.False(t, ok)
+}
+
+func TestRegistry_Unwatch(t *testing.T) {
+	reg := spectate.NewRegistry()
+	g, _ := game.New(5, 5)
+	s := reg.Publish("a", "alice", g)
+
+	updates, _ := s.Watch("b")
+	other, _ := s.Watch("c")
+	reg.Unwatch("b")
+
+	_, ok := <-updates
+	assert.False(t, ok)
+	assert.Equal(t, 1, s.Watchers())
+
+	s.Changed()
+	_, ok = <-other
+	assert.True(t, ok)
+}
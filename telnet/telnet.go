@@ -0,0 +1,224 @@
+package telnet
+
+import (
+	"bufio"
+	"bytes"
+	"net"
+	"sync"
+)
+
+// Telnet commands and options, see RFC 854, 857, 858 and 1073.
+const (
+	cmdSE   = 240
+	cmdIP   = 244
+	cmdSB   = 250
+	cmdWill = 251
+	cmdWont = 252
+	cmdDo   = 253
+	cmdDont = 254
+	cmdIAC  = 255
+
+	optEcho     = 1
+	optSGA      = 3
+	optNAWS     = 31
+	maxSBLength = 64
+
+	ctrlC = 0x03
+)
+
+// Conn is a telnet connection to a terminal. Reads return what the user
+// typed with the telnet commands taken out, and writes are escaped, so the
+// connection can be used as the input and output of a Bubble Tea program.
+type Conn struct {
+	net.Conn
+	r *bufio.Reader
+
+	// afterCR is set when the last byte read was a carriage return, which
+	// telnet clients follow with a NUL or a line feed.
+	afterCR bool
+
+	mu       sync.Mutex
+	width    int
+	height   int
+	onResize func(width, height int)
+}
+
+func NewConn(c net.Conn) *Conn {
+	return &Conn{
+		Conn: c,
+		r:    bufio.NewReader(c),
+	}
+}
+
+// Negotiate asks the client for character mode: the server echoes what the
+// user types, there are no go-aheads, and the client reports its window
+// size.
+func (c *Conn) Negotiate() error {
+	_, err := c.Conn.Write([]byte{
+		cmdIAC, cmdWill, optEcho,
+		cmdIAC, cmdWill, optSGA,
+		cmdIAC, cmdDo, optNAWS,
+	})
+	return err
+}
+
+// OnResize registers f to be called whenever the client reports a new
+// window size.
+func (c *Conn) OnResize(f func(width, height int)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.onResize = f
+}
+
+// Size returns the last window size reported by the client, or zeros if it
+// hasn't reported one.
+func (c *Conn) Size() (width, height int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.width, c.height
+}
+
+func (c *Conn) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		// Return what we have instead of blocking for more.
+		if n > 0 && c.r.Buffered() == 0 {
+			break
+		}
+
+		b, err := c.r.ReadByte()
+		if err != nil {
+			if n > 0 {
+				return n, nil
+			}
+			return 0, err
+		}
+
+		if c.afterCR {
+			c.afterCR = false
+			if b == 0 || b == '\n' {
+				continue
+			}
+		}
+
+		switch b {
+		case cmdIAC:
+			data, ok, err := c.command()
+			if err != nil {
+				return n, err
+			}
+			if ok {
+				p[n] = data
+				n++
+			}
+		case '\r':
+			c.afterCR = true
+			p[n] = b
+			n++
+		default:
+			p[n] = b
+			n++
+		}
+	}
+
+	return n, nil
+}
+
+// command handles the command following an IAC. It returns a byte to pass
+// on to the reader if the command stands for one.
+func (c *Conn) command() (byte, bool, error) {
+	cmd, err := c.r.ReadByte()
+	if err != nil {
+		return 0, false, err
+	}
+
+	switch cmd {
+	case cmdIAC:
+		return cmdIAC, true, nil
+	case cmdIP:
+		return ctrlC, true, nil
+	case cmdWill, cmdWont, cmdDo, cmdDont:
+		opt, err := c.r.ReadByte()
+		if err != nil {
+			return 0, false, err
+		}
+		return 0, false, c.option(cmd, opt)
+	case cmdSB:
+		return 0, false, c.subnegotiation()
+	default:
+		// NOP, GA and friends mean nothing to us.
+		return 0, false, nil
+	}
+}
+
+// option answers the client's option requests. Replies to the options we
+// asked for are accepted silently; anything else is refused.
+func (c *Conn) option(cmd, opt byte) error {
+	switch {
+	case cmd == cmdDo && opt != optEcho && opt != optSGA:
+		_, err := c.Conn.Write([]byte{cmdIAC, cmdWont, opt})
+		return err
+	case cmd == cmdWill && opt != optNAWS:
+		_, err := c.Conn.Write([]byte{cmdIAC, cmdDont, opt})
+		return err
+	}
+	return nil
+}
+
+// subnegotiation reads up to IAC SE and handles NAWS window sizes.
+func (c *Conn) subnegotiation() error {
+	var data []byte
+	for {
+		b, err := c.r.ReadByte()
+		if err != nil {
+			return err
+		}
+
+		if b == cmdIAC {
+			next, err := c.r.ReadByte()
+			if err != nil {
+				return err
+			}
+			if next == cmdSE {
+				break
+			}
+			b = next
+		}
+
+		if len(data) < maxSBLength {
+			data = append(data, b)
+		}
+	}
+
+	if len(data) == 5 && data[0] == optNAWS {
+		c.resize(int(data[1])<<8|int(data[2]), int(data[3])<<8|int(data[4]))
+	}
+	return nil
+}
+
+func (c *Conn) resize(width, height int) {
+	c.mu.Lock()
+	c.width, c.height = width, height
+	f := c.onResize
+	c.mu.Unlock()
+
+	if f != nil {
+		f(width, height)
+	}
+}
+
+// Write sends p to the client, escaping bytes that would be taken for
+// telnet commands.
+func (c *Conn) Write(p []byte) (int, error) {
+	if bytes.IndexByte(p, cmdIAC) < 0 {
+		return c.Conn.Write(p)
+	}
+
+	escaped := bytes.ReplaceAll(p, []byte{cmdIAC}, []byte{cmdIAC, cmdIAC})
+	if _, err := c.Conn.Write(escaped); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
@@ -0,0 +1,139 @@
+package telnet_test
+
+import (
+	"github.com/jboewer/minesshweeper/telnet"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+const (
+	se   = 240
+	ip   = 244
+	sb   = 250
+	will = 251
+	wont = 252
+	do   = 253
+	dont = 254
+	iac  = 255
+)
+
+// connect returns both ends of a loopback connection, with the server end
+// speaking telnet.
+func connect(t *testing.T) (*telnet.Conn, net.Conn) {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer ln.Close()
+
+	accepted := make(chan net.Conn)
+	go func() {
+		c, _ := ln.Accept()
+		accepted <- c
+	}()
+
+	client, err := net.Dial("tcp", ln.Addr().String())
+	assert.NoError(t, err)
+	server := <-accepted
+
+	t.Cleanup(func() {
+		client.Close()
+		server.Close()
+	})
+
+	client.SetDeadline(time.Now().Add(5 * time.Second))
+	server.SetDeadline(time.Now().Add(5 * time.Second))
+
+	return telnet.NewConn(server), client
+}
+
+func read(t *testing.T, r io.Reader, n int) []byte {
+	t.Helper()
+
+	buf := make([]byte, n)
+	_, err := io.ReadFull(r, buf)
+	assert.NoError(t, err)
+	return buf
+}
+
+func TestNegotiate(t *testing.T) {
+	server, client := connect(t)
+
+	assert.NoError(t, server.Negotiate())
+
+	assert.Equal(t, []byte{iac, will, 1, iac, will, 3, iac, do, 31}, read(t, client, 9))
+}
+
+func TestRead(t *testing.T) {
+	t.Run("Strips commands", func(t *testing.T) {
+		server, client := connect(t)
+
+		client.Write([]byte{'a', iac, do, 1, iac, will, 31, 'b', iac, 241, 'c'})
+
+		assert.Equal(t, []byte("abc"), read(t, server, 3))
+	})
+	t.Run("Escaped IAC", func(t *testing.T) {
+		server, client := connect(t)
+
+		client.Write([]byte{iac, iac, 'x'})
+
+		assert.Equal(t, []byte{iac, 'x'}, read(t, server, 2))
+	})
+	t.Run("Carriage returns", func(t *testing.T) {
+		server, client := connect(t)
+
+		client.Write([]byte{'\r', 0, 'a', '\r', '\n', 'b', '\r'})
+		client.Write([]byte{0, 'c'})
+
+		assert.Equal(t, []byte("\ra\rb\rc"), read(t, server, 6))
+	})
+	t.Run("Interrupt", func(t *testing.T) {
+		server, client := connect(t)
+
+		client.Write([]byte{iac, ip})
+
+		assert.Equal(t, []byte{0x03}, read(t, server, 1))
+	})
+	t.Run("Refuses unknown options", func(t *testing.T) {
+		server, client := connect(t)
+
+		client.Write([]byte{iac, do, 24, iac, will, 24, 'x'})
+		read(t, server, 1)
+
+		assert.Equal(t, []byte{iac, wont, 24, iac, dont, 24}, read(t, client, 6))
+	})
+}
+
+func TestWindowSize(t *testing.T) {
+	server, client := connect(t)
+
+	sizes := make(chan [2]int, 2)
+	server.OnResize(func(width, height int) {
+		sizes <- [2]int{width, height}
+	})
+
+	client.Write([]byte{iac, sb, 31, 0, 80, 0, 24, iac, se})
+	// 255 columns have to be escaped inside the subnegotiation.
+	client.Write([]byte{iac, sb, 31, 1, iac, iac, 0, 50, iac, se, 'x'})
+	read(t, server, 1)
+
+	assert.Equal(t, [2]int{80, 24}, <-sizes)
+	assert.Equal(t, [2]int{511, 50}, <-sizes)
+
+	width, height := server.Size()
+	assert.Equal(t, 511, width)
+	assert.Equal(t, 50, height)
+}
+
+func TestWrite(t *testing.T) {
+	server, client := connect(t)
+
+	n, err := server.Write([]byte{'a', iac, 'b'})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, n)
+	assert.Equal(t, []byte{'a', iac, iac, 'b'}, read(t, client, 4))
+}
@@ -2,49 +2,65 @@ package tui
 
 import "github.com/charmbracelet/lipgloss"
 
+// getCursorColors returns the theme's colours for the cursor.
 func getCursorColors(
+	t Theme,
 	fg lipgloss.TerminalColor,
 	bg lipgloss.TerminalColor,
 ) (lipgloss.TerminalColor, lipgloss.TerminalColor) {
-	fg = lipgloss.Color("#111")
-	bg = lipgloss.Color("#FF33FF")
+	if t.Cursor.Foreground != "" {
+		fg = terminalColor(t.Cursor.Foreground)
+	}
+	if t.Cursor.Background != "" {
+		bg = terminalColor(t.Cursor.Background)
+	}
 	return fg, bg
 }
 
-func getCellColors(cell string) (lipgloss.TerminalColor, lipgloss.TerminalColor) {
-	var fg lipgloss.TerminalColor = lipgloss.NoColor{}
-	var bg lipgloss.TerminalColor = lipgloss.NoColor{}
+func getPeerCursorColors(t Theme, c lipgloss.Color) (lipgloss.TerminalColor, lipgloss.TerminalColor) {
+	return terminalColor(t.Cursor.Foreground), c
+}
 
+func getCellColors(t Theme, cell string) (lipgloss.TerminalColor, lipgloss.TerminalColor) {
+	c := t.CellColors(cell)
+	return terminalColor(c.Foreground), terminalColor(c.Background)
+}
+
+// decorate crosses out wrong flags and makes the exploded mine bold.
+func decorate(s lipgloss.Style, cell string) lipgloss.Style {
 	switch cell {
-	case "0":
-		fg = lipgloss.Color("#292929")
-	case "1":
-		fg = lipgloss.Color("#74adf2")
-	case "2":
-		fg = lipgloss.Color("#00FF00")
-	case "3":
-		fg = lipgloss.Color("#FF0000")
-	case "4":
-		fg = lipgloss.Color("#28706d")
-	case "5":
-		fg = lipgloss.Color("#b06446")
-	case "6":
-		fg = lipgloss.Color("#FF0000")
-	case "7":
-		fg = lipgloss.Color("#8a7101")
-	case "8":
-		fg = lipgloss.Color("#111")
-		bg = lipgloss.Color("#bfbfbf")
-	case "*":
-		fg = lipgloss.Color("#FF33FF")
-	case "F":
-		bg = lipgloss.Color("#ffee00")
-		fg = lipgloss.Color("#111")
+	case "X":
+		return s.Strikethrough(true)
 	case "B":
-		bg = lipgloss.Color("#FF0000")
-		fg = lipgloss.Color("#111")
-	case " ":
-		bg = lipgloss.Color("#bfbfbf")
+		return s.Bold(true)
 	}
-	return fg, bg
+	return s
+}
+
+// fallbacks replace the classic theme's colours on terminals with 16
+// colours.
+var fallbacks = map[string]struct{ ansi256, ansi string }{
+	"#111":    {"233", "0"},
+	"#292929": {"235", "8"},
+	"#74adf2": {"75", "12"},
+	"#00FF00": {"46", "10"},
+	"#FF0000": {"196", "9"},
+	"#28706d": {"30", "6"},
+	"#b06446": {"131", "1"},
+	"#8a7101": {"136", "3"},
+	"#bfbfbf": {"250", "7"},
+	"#FF33FF": {"207", "13"},
+	"#ffee00": {"226", "11"},
+}
+
+func terminalColor(c string) lipgloss.TerminalColor {
+	if c == "" {
+		return lipgloss.NoColor{}
+	}
+
+	f, ok := fallbacks[c]
+	if !ok {
+		return lipgloss.Color(c)
+	}
+	return lipgloss.CompleteColor{TrueColor: c, ANSI256: f.ansi256, ANSI: f.ansi}
 }
@@ -0,0 +1,180 @@
+package tui
+
+import (
+	"fmt"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+	"github.com/jboewer/minesshweeper/daily"
+	"github.com/jboewer/minesshweeper/leaderboard"
+	"strings"
+)
+
+// NewDailyModel shows today's challenge with its leaderboard, and starts
+// the player's ranked attempt or a practice game. Leaving it returns to the
+// lobby.
+func NewDailyModel(services *Services, back LobbyModel) DailyModel {
+	return DailyModel{
+		services:  services,
+		back:      back,
+		challenge: daily.Today(),
+	}
+}
+
+type DailyModel struct {
+	services  *Services
+	back      LobbyModel
+	challenge daily.Challenge
+	err       error
+}
+
+func (dm DailyModel) Init() tea.Cmd {
+	return nil
+}
+
+func (dm DailyModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if size, ok := msg.(tea.WindowSizeMsg); ok {
+		dm.back.size = size
+		return dm, nil
+	}
+
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return dm, nil
+	}
+
+	dm.err = nil
+
+	switch {
+	case key.Type == tea.KeyCtrlC:
+		return dm, tea.Quit
+	case isBack(key, dm.back.settings.Keys):
+		return dm.back, nil
+	case isSelect(key):
+		return dm.play(true)
+	case key.String() == "p":
+		return dm.play(false)
+	}
+
+	return dm, nil
+}
+
+// play starts a game on today's board, or on a practice board like it.
+// Only ranked games are recorded.
+func (dm DailyModel) play(ranked bool) (tea.Model, tea.Cmd) {
+	player := dm.back.player
+	if ranked {
+		if player.ProfileID == "" {
+			dm.err = errNoProfile
+			return dm, nil
+		}
+		if err := dm.services.Attempts.Start(dm.challenge, player.ProfileID); err != nil {
+			dm.err = err
+			return dm, nil
+		}
+	}
+
+	challenge := dm.challenge
+	if !ranked {
+		challenge = challenge.Practice()
+	}
+	g, err := challenge.NewGame()
+	if err != nil {
+		dm.err = err
+		return dm, nil
+	}
+
+	m := newGameModel(g, challengeMoves{soloMoves{g}}, dm.back.settings)
+	m.Cursor.x = challenge.Start.X
+	m.Cursor.y = challenge.Start.Y
+	// As in races, the seed and the opening give the board away.
+	m.hideSeed = true
+	m = dm.back.published(m)
+	if ranked {
+		m = dm.back.recordedAttempt(m, dm.challenge.Date)
+	}
+	m.back = dm
+
+	return dm.back.start(m)
+}
+
+func (dm DailyModel) View() string {
+	rendered := &strings.Builder{}
+
+	c := dm.challenge
+	fmt.Fprintf(rendered, "Daily Challenge %s\n", c.Date)
+	fmt.Fprintf(rendered, "%s, %dx%d with %d mines\n\n", c.Difficulty.Name, c.Difficulty.Width, c.Difficulty.Height, c.Difficulty.Mines)
+
+	results, err := dm.services.Results.Results()
+	if err != nil {
+		rendered.WriteString("Error: " + err.Error() + "\n")
+		return rendered.String()
+	}
+
+	player := dm.back.player
+	if player.ProfileID != "" {
+		available, err := dm.services.Attempts.Available(c, player.ProfileID)
+		if err != nil {
+			rendered.WriteString("Error: " + err.Error() + "\n")
+			return rendered.String()
+		}
+		if available {
+			rendered.WriteString("Your ranked attempt for today is still open.\n")
+		} else {
+			rendered.WriteString("You have played today's ranked attempt.\n")
+		}
+
+		streak := daily.StreakOf(results, player.ProfileID, c)
+		fmt.Fprintf(rendered, "Streak: %d days played, %d days won\n\n", streak.Played, streak.Won)
+	}
+
+	dm.renderRanking(rendered, results)
+
+	rendered.WriteString("\nEnter: Play Ranked Attempt\n")
+	rendered.WriteString("P: Practice on Another Board\n")
+	rendered.WriteString("Esc: Back\n")
+
+	if dm.err != nil {
+		rendered.WriteString("\nError: " + dm.err.Error() + "\n")
+	}
+
+	return rendered.String()
+}
+
+func (dm DailyModel) renderRanking(rendered *strings.Builder, results []leaderboard.Result) {
+	rows := [][]string{}
+	for i, r := range daily.Ranking(results, dm.challenge.Date) {
+		if i == leaderboardSize {
+			break
+		}
+
+		result, time, speed := "Lost", "-", "-"
+		if r.Won {
+			result = "Cleared"
+			time = fmt.Sprintf("%.1fs", r.Time.Seconds())
+			speed = fmt.Sprintf("%.2f", r.ThreeBVPerSecond())
+		}
+		rows = append(rows, []string{
+			fmt.Sprint(i + 1),
+			playerName(dm.services, r),
+			result,
+			time,
+			speed,
+		})
+	}
+
+	if len(rows) == 0 {
+		rendered.WriteString("Nobody has played today's challenge yet.\n")
+		return
+	}
+
+	tbl := table.New().
+		Border(lipgloss.NormalBorder()).
+		Headers("#", "Player", "Result", "Time", "3BV/s").
+		Rows(rows...).
+		StyleFunc(func(row, col int) lipgloss.Style {
+			return dm.back.settings.Renderer.NewStyle().Padding(0, 1)
+		})
+	rendered.WriteString(tbl.Render())
+	rendered.WriteString("\n")
+}
@@ -0,0 +1,267 @@
+package tui
+
+import (
+	"fmt"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jboewer/minesshweeper/game"
+	"github.com/muesli/termenv"
+	"strings"
+)
+
+// minimapHeight is the height of the mini-map.
+const minimapHeight = 3
+
+// cellStyles caches the style of every kind of cell.
+type cellStyles struct {
+	settings Settings
+	styles   map[cellStyleKey]lipgloss.Style
+	border   lipgloss.Style
+	status   lipgloss.Style
+	summary  lipgloss.Style
+}
+
+type cellStyleKey struct {
+	text   string
+	cursor bool
+	peer   lipgloss.Color
+}
+
+func newCellStyles(settings Settings) *cellStyles {
+	theme := settings.Theme
+	return &cellStyles{
+		settings: settings,
+		styles:   map[cellStyleKey]lipgloss.Style{},
+		border:   settings.Renderer.NewStyle().Foreground(terminalColor(theme.Border)),
+		status:   settings.Renderer.NewStyle().Foreground(terminalColor(theme.Status)),
+		summary: settings.Renderer.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(terminalColor(theme.Border)).
+			Padding(0, 1),
+	}
+}
+
+func (cs *cellStyles) style(key cellStyleKey) lipgloss.Style {
+	if s, ok := cs.styles[key]; ok {
+		return s
+	}
+
+	theme := cs.settings.Theme
+	fg, bg := getCellColors(theme, key.text)
+	if key.cursor {
+		fg, bg = getCursorColors(theme, fg, bg)
+	} else if key.peer != "" {
+		fg, bg = getPeerCursorColors(theme, key.peer)
+	}
+
+	s := decorate(cs.settings.Renderer.NewStyle().Foreground(fg).Background(bg), key.text)
+	cs.styles[key] = s
+	return s
+}
+
+// render draws a cell in the given mode. Cursors get brackets when colours
+// can't show them.
+func (cs *cellStyles) render(key cellStyleKey, mode RenderMode) string {
+	plain := cs.settings.Renderer.ColorProfile() == termenv.Ascii
+
+	left, right := " ", " "
+	if plain || cs.settings.Theme.ShapeCues {
+		switch {
+		case key.cursor:
+			left, right = "[", "]"
+		case key.peer != "":
+			left, right = "(", ")"
+		}
+	}
+
+	text := cs.settings.Theme.Symbol(key.text)
+	switch mode {
+	case RenderMedium:
+		text = left + text
+	case RenderDense:
+		// No room for brackets, so they replace the cell.
+		if plain && left != " " {
+			text = left
+		}
+	default:
+		text = left + text + right
+	}
+
+	return cs.style(key).Render(text)
+}
+
+func (gv GameModel) renderGameGrid(rendered *strings.Builder) {
+	snapshot := gv.Game.Snapshot()
+	l := gv.layout()
+
+	peers := map[game.Coordinate]lipgloss.Color{}
+	if gv.room != nil {
+		for _, p := range gv.room.Players() {
+			if p.ID != gv.player.ID {
+				peers[p.Cursor] = lipgloss.Color(p.Color)
+			}
+		}
+	}
+
+	cell := func(x, y int) string {
+		x, y = l.Column+x, l.Row+y
+		c := snapshot.Cell(x, y)
+		// The mines of a won board are shown flagged.
+		if snapshot.State == game.StateWon && c.State == game.CellStateMine {
+			c.State = game.CellStateFlagged
+		}
+		key := cellStyleKey{text: CellText(c)}
+		if x == gv.Cursor.x && y == gv.Cursor.y {
+			key.cursor = true
+		} else {
+			key.peer = peers[game.Coordinate{X: x, Y: y}]
+		}
+		return gv.styles.render(key, l.Mode)
+	}
+
+	if l.Mode == RenderBordered {
+		renderBordered(rendered, l, gv.styles.border, cell)
+	} else {
+		for y := 0; y < l.Height; y++ {
+			for x := 0; x < l.Width; x++ {
+				rendered.WriteString(cell(x, y))
+			}
+			rendered.WriteString("\n")
+		}
+	}
+
+	if l.Width < snapshot.Width || l.Height < snapshot.Height {
+		gv.renderMinimap(rendered, l)
+	}
+}
+
+// renderBordered draws the cells in a grid of box-drawing lines.
+func renderBordered(rendered *strings.Builder, l GridLayout, border lipgloss.Style, cell func(x, y int) string) {
+	line := func(left, middle, right string) string {
+		return border.Render(left+strings.Repeat("───"+middle, l.Width-1)+"───"+right) + "\n"
+	}
+	top, between, bottom := line("┌", "┬", "┐"), line("├", "┼", "┤"), line("└", "┴", "┘")
+	bar := border.Render("│")
+
+	rendered.WriteString(top)
+	for y := 0; y < l.Height; y++ {
+		if y > 0 {
+			rendered.WriteString(between)
+		}
+		rendered.WriteString(bar)
+		for x := 0; x < l.Width; x++ {
+			rendered.WriteString(cell(x, y) + bar)
+		}
+		rendered.WriteString("\n")
+	}
+	rendered.WriteString(bottom)
+}
+
+// renderMinimap shows which part of a scrolled board is in view.
+func (gv GameModel) renderMinimap(rendered *strings.Builder, l GridLayout) {
+	width, height := gv.Game.GetGridWidth(), gv.Game.GetGridHeight()
+	mapWidth := min(width, 16)
+
+	minimap := make([]string, minimapHeight)
+	for y := range minimap {
+		top := y * height / minimapHeight
+		bottom := max(top+1, (y+1)*height/minimapHeight)
+		for x := 0; x < mapWidth; x++ {
+			left, right := x*width/mapWidth, (x+1)*width/mapWidth
+			if left < l.Column+l.Width && right > l.Column && top < l.Row+l.Height && bottom > l.Row {
+				minimap[y] += "█"
+			} else {
+				minimap[y] += "░"
+			}
+		}
+	}
+
+	arrow := func(more bool, a string) string {
+		if more {
+			return a
+		}
+		return " "
+	}
+	arrows := []string{
+		" " + arrow(l.Row > 0, "▲") + " ",
+		arrow(l.Column > 0, "◀") + " " + arrow(l.Column+l.Width < width, "▶"),
+		" " + arrow(l.Row+l.Height < height, "▼") + " ",
+	}
+
+	position := []string{
+		fmt.Sprintf("Columns %d-%d of %d", l.Column+1, l.Column+l.Width, width),
+		fmt.Sprintf("Rows %d-%d of %d", l.Row+1, l.Row+l.Height, height),
+		"",
+	}
+
+	for y := range minimap {
+		line := minimap[y] + "  " + arrows[y] + "  " + position[y]
+		rendered.WriteString(strings.TrimRight(line, " ") + "\n")
+	}
+}
+
+// nextMode cycles from automatic through every render mode.
+func (gv *GameModel) nextMode() {
+	switch {
+	case gv.autoMode:
+		gv.autoMode = false
+		gv.mode = RenderBordered
+	case gv.mode == RenderDense:
+		gv.autoMode = true
+	default:
+		gv.mode++
+	}
+}
+
+// renderMode returns the mode the board is drawn in.
+func (gv GameModel) renderMode() RenderMode {
+	if !gv.autoMode {
+		return gv.mode
+	}
+
+	for _, m := range []RenderMode{RenderBordered, RenderMedium} {
+		cols, rows := gv.visible(m)
+		if cols == gv.Game.GetGridWidth() && rows == gv.Game.GetGridHeight() {
+			return m
+		}
+	}
+	return RenderDense
+}
+
+// visible returns how many cells fit in the terminal in the given mode.
+func (gv GameModel) visible(mode RenderMode) (cols, rows int) {
+	cols, rows = gv.Game.GetGridWidth(), gv.Game.GetGridHeight()
+	width, height := mode.cellSize()
+	border := mode.border()
+
+	if gv.width > 0 {
+		cols = min(cols, max(1, (gv.width-border)/width))
+	}
+	if gv.height > 0 {
+		lines := gv.height - gv.top - gv.bottom - gv.helpHeight() - border
+		if cols < gv.Game.GetGridWidth() || lines/height < rows {
+			lines -= minimapHeight
+		}
+		rows = min(rows, max(1, lines/height))
+	}
+	return cols, rows
+}
+
+// follow scrolls the board so that the cursor is in view.
+func (gv *GameModel) follow() {
+	cols, rows := gv.visible(gv.renderMode())
+	gv.scrollX = scroll(gv.scrollX, cols, gv.Game.GetGridWidth(), gv.Cursor.x)
+	gv.scrollY = scroll(gv.scrollY, rows, gv.Game.GetGridHeight(), gv.Cursor.y)
+}
+
+func (gv GameModel) layout() GridLayout {
+	mode := gv.renderMode()
+	cols, rows := gv.visible(mode)
+	return GridLayout{
+		Top:    gv.top,
+		Column: gv.scrollX,
+		Row:    gv.scrollY,
+		Width:  cols,
+		Height: rows,
+		Mode:   mode,
+	}
+}
@@ -0,0 +1,22 @@
+package tui
+
+import tea "github.com/charmbracelet/bubbletea"
+
+// editLine applies a key press to a single line of text input. Only runes
+// accepted by allow are added, up to max of them.
+func editLine(value string, msg tea.KeyMsg, max int, allow func(rune) bool) string {
+	switch msg.Type {
+	case tea.KeyBackspace:
+		if r := []rune(value); len(r) > 0 {
+			return string(r[:len(r)-1])
+		}
+	case tea.KeyRunes:
+		for _, c := range msg.Runes {
+			if len([]rune(value)) < max && allow(c) {
+				value += string(c)
+			}
+		}
+	}
+
+	return value
+}
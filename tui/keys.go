@@ -0,0 +1,350 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"gopkg.in/yaml.v3"
+	"os"
+	"strings"
+	"unicode"
+)
+
+// KeyMap decides which keys play the game. Ctrl+C always quits. Reset lays
+// out a new board, Retry plays the same board again, Seed asks for the seed
+// of a board to play and Copy copies the seed to the clipboard. Ranking
+// switches how the leaderboards are ranked.
+type KeyMap struct {
+	Name    string
+	Up      key.Binding
+	Down    key.Binding
+	Left    key.Binding
+	Right   key.Binding
+	Flag    key.Binding
+	Reveal  key.Binding
+	View    key.Binding
+	Reset   key.Binding
+	Retry   key.Binding
+	Seed    key.Binding
+	Copy    key.Binding
+	Ranking key.Binding
+	Help    key.Binding
+	Quit    key.Binding
+}
+
+// action is a binding of a key map by the name it has in key map files.
+type action struct {
+	name    string
+	desc    string
+	binding func(k *KeyMap) *key.Binding
+}
+
+var actions = []action{
+	{"up", "up", func(k *KeyMap) *key.Binding { return &k.Up }},
+	{"down", "down", func(k *KeyMap) *key.Binding { return &k.Down }},
+	{"left", "left", func(k *KeyMap) *key.Binding { return &k.Left }},
+	{"right", "right", func(k *KeyMap) *key.Binding { return &k.Right }},
+	{"flag", "flag", func(k *KeyMap) *key.Binding { return &k.Flag }},
+	{"reveal", "reveal", func(k *KeyMap) *key.Binding { return &k.Reveal }},
+	{"view", "view", func(k *KeyMap) *key.Binding { return &k.View }},
+	{"reset", "new board", func(k *KeyMap) *key.Binding { return &k.Reset }},
+	{"retry", "retry", func(k *KeyMap) *key.Binding { return &k.Retry }},
+	{"seed", "enter seed", func(k *KeyMap) *key.Binding { return &k.Seed }},
+	{"copy", "copy seed", func(k *KeyMap) *key.Binding { return &k.Copy }},
+	{"ranking", "toggle ranking", func(k *KeyMap) *key.Binding { return &k.Ranking }},
+	{"help", "more", func(k *KeyMap) *key.Binding { return &k.Help }},
+	{"quit", "quit", func(k *KeyMap) *key.Binding { return &k.Quit }},
+}
+
+// newKeyMap binds the directions to each group of keys, given in the order
+// up, left, down and right. Every other action has the same key in all
+// presets.
+func newKeyMap(name string, directions ...[4]string) KeyMap {
+	k := KeyMap{
+		Name:    name,
+		Flag:    key.NewBinding(key.WithKeys("f")),
+		Reveal:  key.NewBinding(key.WithKeys(" ")),
+		View:    key.NewBinding(key.WithKeys("v")),
+		Reset:   key.NewBinding(key.WithKeys("r")),
+		Retry:   key.NewBinding(key.WithKeys("R")),
+		Seed:    key.NewBinding(key.WithKeys("S")),
+		Copy:    key.NewBinding(key.WithKeys("c")),
+		Ranking: key.NewBinding(key.WithKeys("t")),
+		Help:    key.NewBinding(key.WithKeys("?")),
+		Quit:    key.NewBinding(key.WithKeys("q")),
+	}
+	for _, d := range directions {
+		for i, b := range []*key.Binding{&k.Up, &k.Left, &k.Down, &k.Right} {
+			b.SetKeys(append(b.Keys(), d[i])...)
+		}
+	}
+	k.describe()
+	return k
+}
+
+var (
+	vimDirections    = [4]string{"k", "h", "j", "l"}
+	wasdDirections   = [4]string{"w", "a", "s", "d"}
+	arrowDirections  = [4]string{"up", "left", "down", "right"}
+	azertyDirections = [4]string{"z", "q", "s", "d"}
+
+	DefaultKeys = newKeyMap("default", wasdDirections, vimDirections, arrowDirections)
+	VimKeys     = newKeyMap("vim", vimDirections)
+	WASDKeys    = newKeyMap("wasd", wasdDirections)
+	ArrowKeys   = newKeyMap("arrows", arrowDirections)
+	// AZERTYKeys moves with ZQSD, so it quits with Esc instead of Q.
+	AZERTYKeys = func() KeyMap {
+		k := newKeyMap("azerty", azertyDirections)
+		k.Quit.SetKeys("esc")
+		k.describe()
+		return k
+	}()
+
+	// KeyMaps are the key maps that come with the game.
+	KeyMaps = []KeyMap{DefaultKeys, VimKeys, WASDKeys, ArrowKeys, AZERTYKeys}
+)
+
+// mouseHelp only explains the mouse in the full help. No key is called
+// "mouse", so it never matches.
+var mouseHelp = []key.Binding{
+	key.NewBinding(key.WithKeys("mouse"), key.WithHelp("click", "reveal")),
+	key.NewBinding(key.WithKeys("mouse"), key.WithHelp("right click", "flag")),
+	key.NewBinding(key.WithKeys("mouse"), key.WithHelp("middle click", "chord")),
+}
+
+// describe labels the bindings with their keys, so that the help always
+// shows the keys that are bound.
+func (k *KeyMap) describe() {
+	for _, a := range actions {
+		b := a.binding(k)
+		b.SetHelp(keyNames(b.Keys()), a.desc)
+	}
+}
+
+// ShortHelp implements help.KeyMap.
+func (k KeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.move(), k.Reveal, k.Flag, k.Help, k.Quit}
+}
+
+// FullHelp implements help.KeyMap.
+func (k KeyMap) FullHelp() [][]key.Binding {
+	help := k.Help
+	help.SetHelp(help.Help().Key, "less")
+	return [][]key.Binding{
+		{k.Up, k.Down, k.Left, k.Right},
+		{k.Reveal, k.Flag, k.View},
+		mouseHelp,
+		{k.Reset, k.Retry, k.Seed, k.Copy},
+		{help, k.Quit},
+	}
+}
+
+// move sums up the directions as one binding, such as wasd/khjl.
+func (k KeyMap) move() key.Binding {
+	directions := [][]string{k.Up.Keys(), k.Left.Keys(), k.Down.Keys(), k.Right.Keys()}
+	n := len(directions[0])
+	for _, keys := range directions {
+		n = min(n, len(keys))
+	}
+
+	groups := make([]string, n)
+	for i := range groups {
+		for _, keys := range directions {
+			groups[i] += keyName(keys[i])
+		}
+	}
+	return key.NewBinding(key.WithKeys(k.Up.Keys()...), key.WithHelp(strings.Join(groups, "/"), "move"))
+}
+
+func keyNames(keys []string) string {
+	names := make([]string, len(keys))
+	for i, k := range keys {
+		names[i] = keyName(k)
+	}
+	return strings.Join(names, "/")
+}
+
+func keyName(k string) string {
+	switch k {
+	case " ":
+		return "space"
+	case "up":
+		return "↑"
+	case "down":
+		return "↓"
+	case "left":
+		return "←"
+	case "right":
+		return "→"
+	}
+	return k
+}
+
+// keyHint is how screens without the help list a key, such as "Q: Quit".
+func keyHint(b key.Binding, desc string) string {
+	label := []rune(b.Help().Key)
+	if len(label) > 0 {
+		label[0] = unicode.ToUpper(label[0])
+	}
+	return string(label) + ": " + desc
+}
+
+// Validate checks that every action has a key and that no key is bound to
+// more than one action.
+func (k KeyMap) Validate() error {
+	if k.Name == "" {
+		return errors.New("key map has no name")
+	}
+
+	var errs []error
+	bound := map[string]string{}
+	for _, a := range actions {
+		keys := a.binding(&k).Keys()
+		if len(keys) == 0 {
+			errs = append(errs, fmt.Errorf("keys %s: nothing is bound to %s", k.Name, a.name))
+		}
+		for _, name := range keys {
+			if name == "ctrl+c" {
+				errs = append(errs, fmt.Errorf("keys %s: ctrl+c always quits", k.Name))
+				continue
+			}
+			if other, ok := bound[name]; ok && other != a.name {
+				errs = append(errs, fmt.Errorf("keys %s: %s is bound to both %s and %s", k.Name, keyName(name), other, a.name))
+				continue
+			}
+			bound[name] = a.name
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// keyMapFile is a key map as it is written in a file. Actions it leaves out
+// keep the keys of its preset.
+type keyMapFile struct {
+	Name   string `yaml:"name"`
+	Preset string `yaml:"preset"`
+	// Keys are the keys of each action, by the action's name. Space is
+	// written as "space".
+	Keys map[string][]string `yaml:",inline"`
+}
+
+// LoadKeyMaps reads a YAML file with a list of custom key maps.
+func LoadKeyMaps(path string) ([]KeyMap, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []keyMapFile
+	if err := yaml.Unmarshal(data, &files); err != nil {
+		return nil, err
+	}
+
+	keyMaps := make([]KeyMap, 0, len(files))
+	for _, f := range files {
+		k, err := f.keyMap()
+		if err != nil {
+			return nil, err
+		}
+		if err := k.Validate(); err != nil {
+			return nil, err
+		}
+		_, builtIn := FindKeyMap(KeyMaps, k.Name)
+		if _, ok := FindKeyMap(keyMaps, k.Name); ok || builtIn {
+			return nil, fmt.Errorf("keys %s already exist", k.Name)
+		}
+		keyMaps = append(keyMaps, k)
+	}
+
+	return keyMaps, nil
+}
+
+func (f keyMapFile) keyMap() (KeyMap, error) {
+	k := DefaultKeys
+	if f.Preset != "" {
+		preset, ok := FindKeyMap(KeyMaps, f.Preset)
+		if !ok {
+			return KeyMap{}, fmt.Errorf("keys %s: unknown preset %s", f.Name, f.Preset)
+		}
+		k = preset
+	}
+	k.Name = f.Name
+
+	for name, keys := range f.Keys {
+		i := findAction(name)
+		if i < 0 {
+			return KeyMap{}, fmt.Errorf("keys %s: unknown action %s", f.Name, name)
+		}
+		for j, key := range keys {
+			if strings.EqualFold(key, "space") {
+				keys[j] = " "
+			}
+		}
+		actions[i].binding(&k).SetKeys(keys...)
+	}
+	k.describe()
+
+	return k, nil
+}
+
+func findAction(name string) int {
+	for i, a := range actions {
+		if a.name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// FindKeyMap looks up a key map by its name.
+func FindKeyMap(keyMaps []KeyMap, name string) (KeyMap, bool) {
+	for _, k := range keyMaps {
+		if strings.EqualFold(k.Name, name) {
+			return k, true
+		}
+	}
+	return KeyMap{}, false
+}
+
+// newHelp shows the keys with the player's renderer and theme.
+func newHelp(settings Settings) help.Model {
+	h := help.New()
+	r := settings.Renderer
+	keyStyle := r.NewStyle().Foreground(terminalColor(settings.Theme.Status)).Bold(true)
+	descStyle := r.NewStyle()
+	faint := r.NewStyle().Faint(true)
+	h.Styles = help.Styles{
+		Ellipsis:       faint,
+		ShortKey:       keyStyle,
+		ShortDesc:      descStyle,
+		ShortSeparator: faint,
+		FullKey:        keyStyle,
+		FullDesc:       descStyle,
+		FullSeparator:  faint,
+	}
+	return h
+}
+
+// pairHint lists two bindings key by key, such as "WS/KJ/↑↓: Move Around".
+func pairHint(a, b key.Binding, desc string) string {
+	aKeys, bKeys := a.Keys(), b.Keys()
+	groups := make([]string, min(len(aKeys), len(bKeys)))
+	for i := range groups {
+		groups[i] = strings.ToUpper(keyName(aKeys[i]) + keyName(bKeys[i]))
+	}
+	return strings.Join(groups, "/") + ": " + desc
+}
+
+// isBack tells whether msg leaves a screen other than the game. Esc always
+// does, next to the player's quit keys.
+func isBack(msg tea.KeyMsg, keys KeyMap) bool {
+	return msg.Type == tea.KeyEsc || key.Matches(msg, keys.Quit)
+}
+
+// isSelect tells whether msg picks the selected item of a list.
+func isSelect(msg tea.KeyMsg) bool {
+	return msg.Type == tea.KeyEnter || msg.String() == " "
+}
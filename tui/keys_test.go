@@ -0,0 +1,81 @@
+package tui_test
+
+import (
+	"github.com/jboewer/minesshweeper/tui"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBuiltInKeyMaps(t *testing.T) {
+	for _, k := range tui.KeyMaps {
+		assert.NoError(t, k.Validate(), k.Name)
+	}
+
+	assert.Equal(t, []string{"w", "k", "up"}, tui.DefaultKeys.Up.Keys())
+	assert.Equal(t, []string{"q"}, tui.AZERTYKeys.Left.Keys())
+	assert.Equal(t, "space", tui.DefaultKeys.Reveal.Help().Key)
+	assert.Equal(t, "wasd/khjl/↑←↓→", tui.DefaultKeys.ShortHelp()[0].Help().Key)
+}
+
+func TestKeyMap_Validate(t *testing.T) {
+	k := tui.VimKeys
+	k.Flag.SetKeys("j")
+	k.Reset.SetKeys("v")
+	k.Help.SetKeys()
+
+	err := k.Validate()
+
+	assert.ErrorContains(t, err, "j is bound to both down and flag")
+	assert.ErrorContains(t, err, "v is bound to both view and reset")
+	assert.ErrorContains(t, err, "nothing is bound to help")
+}
+
+func TestLoadKeyMaps(t *testing.T) {
+	write := func(t *testing.T, content string) string {
+		path := filepath.Join(t.TempDir(), "keys.yaml")
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+		return path
+	}
+
+	t.Run("Custom keys start from a preset", func(t *testing.T) {
+		keyMaps, err := tui.LoadKeyMaps(write(t, `
+- name: one-handed
+  preset: wasd
+  flag: [e]
+  reveal: [space, q]
+  quit: [esc]
+`))
+
+		assert.NoError(t, err)
+		assert.Len(t, keyMaps, 1)
+		assert.Equal(t, "one-handed", keyMaps[0].Name)
+		assert.Equal(t, tui.WASDKeys.Up.Keys(), keyMaps[0].Up.Keys())
+		assert.Equal(t, []string{" ", "q"}, keyMaps[0].Reveal.Keys())
+		assert.Equal(t, "space/q", keyMaps[0].Reveal.Help().Key)
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		for _, content := range []string{
+			"- flag: [e]\n",
+			"- name: x\n  flag: [r]\n",
+			"- name: x\n  quit: [ctrl+c]\n",
+			"- name: x\n  jump: [e]\n",
+			"- name: x\n  preset: dvorak\n",
+			"- name: Vim\n",
+			"- name: x\n- name: x\n",
+			"name: x\n",
+		} {
+			_, err := tui.LoadKeyMaps(write(t, content))
+			assert.Error(t, err, content)
+		}
+	})
+}
+
+func TestServices_KeyMap(t *testing.T) {
+	services := &tui.Services{}
+
+	assert.Equal(t, "vim", services.KeyMap("VIM").Name)
+	assert.Equal(t, "default", services.KeyMap("").Name)
+	assert.Equal(t, "default", services.KeyMap("missing").Name)
+}
@@ -0,0 +1,87 @@
+package tui
+
+import "github.com/jboewer/minesshweeper/game"
+
+// RenderMode is how densely a board is drawn.
+type RenderMode int
+
+const (
+	// RenderBordered draws cells three columns wide with borders.
+	RenderBordered RenderMode = iota
+	// RenderMedium draws cells two columns wide.
+	RenderMedium
+	// RenderDense draws cells one column wide.
+	RenderDense
+)
+
+var renderModeNames = map[RenderMode]string{
+	RenderBordered: "bordered",
+	RenderMedium:   "medium",
+	RenderDense:    "dense",
+}
+
+func (m RenderMode) String() string {
+	return renderModeNames[m]
+}
+
+// cellSize returns the columns and lines of a cell and its border.
+func (m RenderMode) cellSize() (width, height int) {
+	switch m {
+	case RenderMedium:
+		return 2, 1
+	case RenderDense:
+		return 1, 1
+	default:
+		return 4, 2
+	}
+}
+
+// border returns the columns and lines before the first cell.
+func (m RenderMode) border() int {
+	if m == RenderBordered {
+		return 1
+	}
+	return 0
+}
+
+// GridLayout describes where a board is drawn on the screen.
+type GridLayout struct {
+	// Left and Top are the screen position of the grid's outer corner.
+	Left, Top int
+	// Column and Row are the first cell shown when the board is scrolled.
+	Column, Row int
+	// Width and Height are the number of cells shown.
+	Width, Height int
+	Mode          RenderMode
+}
+
+// CellAt returns the cell drawn at the screen position x, y, if any.
+func (l GridLayout) CellAt(x, y int) (game.Coordinate, bool) {
+	x -= l.Left
+	y -= l.Top
+	if x < 0 || y < 0 {
+		return game.Coordinate{}, false
+	}
+
+	width, height := l.Mode.cellSize()
+	if l.Mode == RenderBordered && (x%width == 0 || y%height == 0) {
+		return game.Coordinate{}, false
+	}
+
+	x, y = x/width, y/height
+	if x >= l.Width || y >= l.Height {
+		return game.Coordinate{}, false
+	}
+	return game.Coordinate{X: l.Column + x, Y: l.Row + y}, true
+}
+
+// scroll returns the first of size cells to show so that cell is visible.
+func scroll(first, size, total, cell int) int {
+	if cell < first {
+		first = cell
+	}
+	if cell >= first+size {
+		first = cell - size + 1
+	}
+	return max(0, min(first, total-size))
+}
@@ -0,0 +1,66 @@
+package tui_test
+
+import (
+	"github.com/jboewer/minesshweeper/game"
+	"github.com/jboewer/minesshweeper/tui"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestGridLayout_CellAt(t *testing.T) {
+	l := tui.GridLayout{Left: 0, Top: 2, Width: 3, Height: 2}
+
+	for _, tc := range []struct {
+		name string
+		x, y int
+		want game.Coordinate
+		ok   bool
+	}{
+		{"First cell", 2, 3, game.Coordinate{X: 0, Y: 0}, true},
+		{"Left padding", 1, 3, game.Coordinate{X: 0, Y: 0}, true},
+		{"Right padding", 3, 3, game.Coordinate{X: 0, Y: 0}, true},
+		{"Last cell", 10, 5, game.Coordinate{X: 2, Y: 1}, true},
+		{"Outer border", 0, 3, game.Coordinate{}, false},
+		{"Column border", 4, 3, game.Coordinate{}, false},
+		{"Row border", 2, 4, game.Coordinate{}, false},
+		{"Above the grid", 2, 1, game.Coordinate{}, false},
+		{"Right of the grid", 13, 3, game.Coordinate{}, false},
+		{"Below the grid", 2, 7, game.Coordinate{}, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c, ok := l.CellAt(tc.x, tc.y)
+
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.want, c)
+		})
+	}
+
+	t.Run("Medium", func(t *testing.T) {
+		l := tui.GridLayout{Top: 1, Width: 3, Height: 2, Mode: tui.RenderMedium}
+
+		c, ok := l.CellAt(3, 2)
+		assert.True(t, ok)
+		assert.Equal(t, game.Coordinate{X: 1, Y: 1}, c)
+
+		_, ok = l.CellAt(6, 1)
+		assert.False(t, ok)
+	})
+	t.Run("Dense", func(t *testing.T) {
+		l := tui.GridLayout{Width: 3, Height: 2, Mode: tui.RenderDense}
+
+		c, ok := l.CellAt(2, 0)
+		assert.True(t, ok)
+		assert.Equal(t, game.Coordinate{X: 2, Y: 0}, c)
+
+		_, ok = l.CellAt(0, 2)
+		assert.False(t, ok)
+	})
+	t.Run("Scrolled", func(t *testing.T) {
+		l := tui.GridLayout{Top: 2, Column: 10, Row: 5, Width: 3, Height: 2}
+
+		c, ok := l.CellAt(6, 5)
+
+		assert.True(t, ok)
+		assert.Equal(t, game.Coordinate{X: 11, Y: 6}, c)
+	})
+}
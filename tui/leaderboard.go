@@ -0,0 +1,146 @@
+package tui
+
+import (
+	"fmt"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+	"github.com/jboewer/minesshweeper/game"
+	"github.com/jboewer/minesshweeper/leaderboard"
+	"strings"
+)
+
+const leaderboardSize = 10
+
+// NewLeaderboardModel shows the best players per difficulty. Leaving the
+// leaderboard returns to back, or quits without one.
+func NewLeaderboardModel(services *Services, back tea.Model, settings Settings) LeaderboardModel {
+	return LeaderboardModel{
+		services: services,
+		back:     back,
+		settings: settings,
+	}
+}
+
+type LeaderboardModel struct {
+	services   *Services
+	back       tea.Model
+	settings   Settings
+	difficulty int
+	ranking    leaderboard.Ranking
+}
+
+func (lm LeaderboardModel) Init() tea.Cmd {
+	return nil
+}
+
+func (lm LeaderboardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if _, ok := msg.(tea.WindowSizeMsg); ok {
+		if lm.back != nil {
+			lm.back, _ = lm.back.Update(msg)
+		}
+		return lm, nil
+	}
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return lm, nil
+	}
+
+	keys := lm.settings.Keys
+	switch {
+	case keyMsg.Type == tea.KeyCtrlC:
+		return lm, tea.Quit
+	case isBack(keyMsg, keys):
+		if lm.back == nil {
+			return lm, tea.Quit
+		}
+		return lm.back, nil
+	case key.Matches(keyMsg, keys.Left):
+		n := len(lm.boards())
+		lm.difficulty = (lm.difficulty + n - 1) % n
+	case key.Matches(keyMsg, keys.Right):
+		lm.difficulty = (lm.difficulty + 1) % len(lm.boards())
+	case key.Matches(keyMsg, keys.Ranking):
+		if lm.ranking == leaderboard.ByTime {
+			lm.ranking = leaderboard.ByThreeBVPerSecond
+		} else {
+			lm.ranking = leaderboard.ByTime
+		}
+	}
+
+	return lm, nil
+}
+
+// boards lists the presets, the server's own board and every board games
+// were recorded on.
+func (lm LeaderboardModel) boards() []game.Difficulty {
+	var extra []game.Difficulty
+	if lm.services.Difficulty.Width > 0 {
+		extra = append(extra, lm.services.Difficulty)
+	}
+
+	results, err := lm.services.Results.Results()
+	if err != nil {
+		return leaderboard.Boards(nil, extra...)
+	}
+	return leaderboard.Boards(results, extra...)
+}
+
+// playerName prefers the player's current nickname over the one they had
+// when the game was recorded.
+func playerName(services *Services, r leaderboard.Result) string {
+	if r.Unverified {
+		return r.Label()
+	}
+	if p, err := services.Profiles.Get(r.PlayerID); err == nil {
+		return p.Name
+	}
+	return r.Name
+}
+
+func (lm LeaderboardModel) View() string {
+	rendered := &strings.Builder{}
+
+	results, err := lm.services.Results.Results()
+	if err != nil {
+		rendered.WriteString("Error: " + err.Error() + "\n")
+		return rendered.String()
+	}
+
+	boards := lm.boards()
+	d := boards[lm.difficulty%len(boards)]
+	fmt.Fprintf(rendered, "Leaderboard: %s by %s\n", d.Name, lm.ranking)
+
+	rows := [][]string{}
+	for i, r := range leaderboard.Best(results, d, lm.ranking, leaderboardSize) {
+		rows = append(rows, []string{
+			fmt.Sprint(i + 1),
+			playerName(lm.services, r),
+			fmt.Sprintf("%.1fs", r.Time.Seconds()),
+			fmt.Sprintf("%.2f", r.ThreeBVPerSecond()),
+			r.PlayedAt.Format("2006-01-02"),
+		})
+	}
+
+	if len(rows) == 0 {
+		rendered.WriteString("\nNo games won yet.\n")
+	} else {
+		tbl := table.New().
+			Border(lipgloss.NormalBorder()).
+			Headers("#", "Player", "Time", "3BV/s", "Date").
+			Rows(rows...).
+			StyleFunc(func(row, col int) lipgloss.Style {
+				return lm.settings.Renderer.NewStyle().Padding(0, 1)
+			})
+		rendered.WriteString(tbl.Render())
+		rendered.WriteString("\n")
+	}
+
+	rendered.WriteString("\n" + pairHint(lm.settings.Keys.Left, lm.settings.Keys.Right, "Change Difficulty") + "\n")
+	rendered.WriteString(keyHint(lm.settings.Keys.Ranking, "Toggle Ranking") + "\n")
+	rendered.WriteString("Esc: Back\n")
+
+	return rendered.String()
+}
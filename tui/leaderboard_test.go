@@ -0,0 +1,28 @@
+package tui_test
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jboewer/minesshweeper/game"
+	"github.com/jboewer/minesshweeper/leaderboard"
+	"github.com/jboewer/minesshweeper/profile"
+	"github.com/jboewer/minesshweeper/tui"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"testing"
+)
+
+func TestLeaderboardModel_Boards(t *testing.T) {
+	store := leaderboard.NewMemoryStore()
+	store.Record(leaderboard.Result{PlayerID: "p", Name: "alice", Width: 20, Height: 20, Mines: 50, Won: true})
+	services := &tui.Services{Results: store, Profiles: profile.NewMemoryStore(), Difficulty: game.DifficultyOf(10, 10, 10)}
+	m := tui.NewLeaderboardModel(services, nil, tui.DefaultSettings(lipgloss.NewRenderer(io.Discard)))
+
+	assert.Contains(t, press(m, "d", "d", "d").View(), "Leaderboard: Custom 10x10/10")
+	view := press(m, "a").View()
+	assert.Contains(t, view, "Leaderboard: Custom 20x20/50")
+	assert.Contains(t, view, "alice")
+
+	resized, _ := m.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	assert.Contains(t, press(resized, "t").View(), "by 3BV/s")
+}
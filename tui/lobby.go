@@ -0,0 +1,438 @@
+package tui
+
+import (
+	"fmt"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jboewer/minesshweeper/game"
+	"github.com/jboewer/minesshweeper/leaderboard"
+	"github.com/jboewer/minesshweeper/spectate"
+	"strings"
+	"unicode"
+)
+
+const roomCodeLength = 4
+
+var lobbyItems = []string{
+	"Play alone",
+	"Create room",
+	"Join room",
+	"Create race",
+	"Join race",
+	"Daily challenge",
+	"Watch a game",
+	"Leaderboards",
+	"Profile",
+	"Quit",
+}
+
+// NewLobbyModel returns the screen shown to SSH players before a game
+// starts. Everything it leads to is drawn with the player's settings.
+func NewLobbyModel(services *Services, player Player, settings Settings) LobbyModel {
+	return LobbyModel{
+		services: services,
+		player:   player,
+		settings: settings,
+	}
+}
+
+type LobbyModel struct {
+	services *Services
+	player   Player
+	settings Settings
+
+	selected int
+	// joining is the lobby item the player is entering a code for.
+	joining string
+	code    string
+	// watching is set while the player picks a game to watch.
+	watching bool
+	watched  int
+	err      error
+	// size is the last window size. Bubble Tea only sends it when the
+	// program starts and on resizes, so the lobby passes it on to the
+	// screens it opens.
+	size tea.WindowSizeMsg
+}
+
+func (lm LobbyModel) Init() tea.Cmd {
+	return nil
+}
+
+func (lm LobbyModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if size, ok := msg.(tea.WindowSizeMsg); ok {
+		lm.size = size
+		return lm, nil
+	}
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return lm, nil
+	}
+
+	if keyMsg.String() == "ctrl+c" {
+		return lm, tea.Quit
+	}
+
+	if lm.joining != "" {
+		return lm.updateJoining(keyMsg)
+	}
+	if lm.watching {
+		return lm.updateWatching(keyMsg)
+	}
+
+	keys := lm.settings.Keys
+	switch {
+	case key.Matches(keyMsg, keys.Quit):
+		return lm, tea.Quit
+	case key.Matches(keyMsg, keys.Up):
+		if lm.selected > 0 {
+			lm.selected--
+		}
+	case key.Matches(keyMsg, keys.Down):
+		if lm.selected < len(lobbyItems)-1 {
+			lm.selected++
+		}
+	case isSelect(keyMsg):
+		return lm.choose()
+	}
+
+	return lm, nil
+}
+
+func (lm LobbyModel) choose() (tea.Model, tea.Cmd) {
+	lm.err = nil
+
+	switch lobbyItems[lm.selected] {
+	case "Play alone":
+		return lm.start(lm.menu())
+	case "Create room":
+		g, err := game.NewWithDifficulty(lm.Difficulty())
+		if err != nil {
+			lm.err = err
+			return lm, nil
+		}
+		r, p, updates := lm.services.Rooms.Create(g, lm.player.SessionID, lm.player.Name)
+		return lm.start(lm.joinedRoom(NewRoomGameModel(r, p, updates, lm.settings)))
+	case "Create race":
+		m, updates, err := lm.services.Matches.Create(lm.services.Race, lm.player.SessionID, lm.player.Name)
+		if err != nil {
+			lm.err = err
+			return lm, nil
+		}
+		return lm.start(lm.joinedRace(NewVersusModel(m, lm.player.SessionID, updates, lm.settings)))
+	case "Join room", "Join race":
+		lm.joining = lobbyItems[lm.selected]
+		lm.code = ""
+	case "Watch a game":
+		lm.watching = true
+		lm.watched = 0
+	case "Daily challenge":
+		return lm.start(lm.Daily())
+	case "Leaderboards":
+		return lm.start(NewLeaderboardModel(lm.services, lm, lm.settings))
+	case "Profile":
+		if lm.player.ProfileID == "" {
+			lm.err = errNoProfile
+			return lm, nil
+		}
+		return lm.start(NewProfileModel(lm.services, lm))
+	case "Quit":
+		return lm, tea.Quit
+	}
+
+	return lm, nil
+}
+
+// PlayAlone starts a game on d for the player, as if they had picked it in
+// the lobby. Leaving the game returns to the lobby.
+func (lm LobbyModel) PlayAlone(d game.Difficulty) (tea.Model, error) {
+	m, err := lm.playAlone(d)
+	if err != nil {
+		return nil, err
+	}
+	m.back = lm
+	return m, nil
+}
+
+// PlaySeed starts a game on the board of size d laid out from seed. It
+// isn't recorded, since the player may already know where the mines are.
+func (lm LobbyModel) PlaySeed(d game.Difficulty, seed int64) (tea.Model, error) {
+	g, err := game.NewWithDifficulty(d, game.WithSeed(seed))
+	if err != nil {
+		return nil, err
+	}
+	m := lm.published(NewGameModel(g, lm.settings))
+	m.back = lm
+	return m, nil
+}
+
+func (lm LobbyModel) playAlone(d game.Difficulty) (GameModel, error) {
+	g, err := game.NewWithDifficulty(d)
+	if err != nil {
+		return GameModel{}, err
+	}
+	return lm.recorded(lm.published(NewGameModel(g, lm.settings))), nil
+}
+
+// menu lets the player pick a board for a game alone, starting from the
+// one they prefer.
+func (lm LobbyModel) menu() MenuModel {
+	m := NewMenuModel(lm.services, lm.player, lm.settings)
+	m.items = boardItems
+	m.back = lm
+	m.size = lm.size
+	m.play = func(d game.Difficulty) (GameModel, error) {
+		return lm.playAlone(d)
+	}
+	m.prefer(lm.Difficulty())
+	return m
+}
+
+// Daily opens today's daily challenge.
+func (lm LobbyModel) Daily() tea.Model {
+	return NewDailyModel(lm.services, lm)
+}
+
+func (lm LobbyModel) updateJoining(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		lm.joining = ""
+	case tea.KeyEnter:
+		return lm.join()
+	default:
+		lm.code = strings.ToUpper(editLine(lm.code, msg, roomCodeLength, isCodeRune))
+	}
+
+	return lm, nil
+}
+
+func (lm LobbyModel) join() (tea.Model, tea.Cmd) {
+	if lm.joining == "Join race" {
+		m, updates, err := lm.services.Matches.Join(lm.code, lm.player.SessionID, lm.player.Name)
+		if err != nil {
+			lm.err = err
+			return lm, nil
+		}
+		return lm.start(lm.joinedRace(NewVersusModel(m, lm.player.SessionID, updates, lm.settings)))
+	}
+
+	r, p, updates, err := lm.services.Rooms.Join(lm.code, lm.player.SessionID, lm.player.Name)
+	if err != nil {
+		lm.err = err
+		return lm, nil
+	}
+	return lm.start(lm.joinedRoom(NewRoomGameModel(r, p, updates, lm.settings)))
+}
+
+func (lm LobbyModel) updateWatching(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	sessions := lm.watchable()
+
+	keys := lm.settings.Keys
+	switch {
+	case isBack(msg, keys):
+		lm.watching = false
+	case key.Matches(msg, keys.Up):
+		if lm.watched > 0 {
+			lm.watched--
+		}
+	case key.Matches(msg, keys.Down):
+		if lm.watched < len(sessions)-1 {
+			lm.watched++
+		}
+	case isSelect(msg):
+		if lm.watched < len(sessions) {
+			return lm.start(NewSpectatorModel(sessions[lm.watched], lm))
+		}
+	}
+
+	return lm, nil
+}
+
+// watchable lists the games of all other players.
+func (lm LobbyModel) watchable() []*spectate.Session {
+	var sessions []*spectate.Session
+	for _, s := range lm.services.Sessions.List() {
+		if s.ID != lm.player.SessionID {
+			sessions = append(sessions, s)
+		}
+	}
+	return sessions
+}
+
+// published makes the game visible to spectators until the player leaves
+// it.
+func (lm LobbyModel) published(m GameModel) GameModel {
+	s := lm.services.Sessions.Publish(lm.player.SessionID, lm.player.Name, m.Game)
+	m.moves = watchedMoves{m.moves, s}
+	m.onLeave(func() {
+		lm.services.Sessions.Remove(lm.player.SessionID)
+	})
+	return m
+}
+
+// Difficulty returns the board the player prefers.
+func (lm LobbyModel) Difficulty() game.Difficulty {
+	if lm.player.ProfileID == "" {
+		return lm.services.Difficulty
+	}
+
+	p, err := lm.services.Profiles.Get(lm.player.ProfileID)
+	if err != nil {
+		return lm.services.Difficulty
+	}
+
+	for _, d := range game.Difficulties {
+		if d.Name == p.Difficulty {
+			return d
+		}
+	}
+	return lm.services.Difficulty
+}
+
+// recorded saves the game to the leaderboard once it ends.
+func (lm LobbyModel) recorded(m GameModel) GameModel {
+	return lm.recordedAttempt(m, "")
+}
+
+// recordedAttempt saves the game as the ranked attempt at the daily
+// challenge of the given date.
+func (lm LobbyModel) recordedAttempt(m GameModel, date string) GameModel {
+	return recorded(m, lm.services.Results, lm.player, date)
+}
+
+// recorded saves the game of a player with a profile to store once it
+// ends, as an attempt at the daily challenge of date if there is one.
+func recorded(m GameModel, store leaderboard.Store, player Player, date string) GameModel {
+	if player.ProfileID == "" || store == nil {
+		return m
+	}
+
+	m.moves = recordedMoves{
+		moves:    m.moves,
+		game:     m.Game,
+		store:    store,
+		player:   player,
+		recorded: new(bool),
+		daily:    date,
+	}
+	return m
+}
+
+// joinedRoom publishes the room's board, and takes the player out of the
+// room when they go back to the lobby.
+func (lm LobbyModel) joinedRoom(m GameModel) GameModel {
+	m.back = lm
+	m.onLeave(func() {
+		lm.services.Rooms.Leave(lm.player.SessionID)
+	})
+	return lm.published(m)
+}
+
+// joinedRace publishes and records the player's board once the race
+// starts, and takes the player out of the match when they go back.
+func (lm LobbyModel) joinedRace(vm VersusModel) VersusModel {
+	vm.publish = func(m GameModel) GameModel {
+		return lm.recorded(lm.published(m))
+	}
+	vm.back = lm
+	vm.leave = func() {
+		lm.services.Matches.Leave(lm.player.SessionID)
+		lm.services.Sessions.Remove(lm.player.SessionID)
+	}
+	return vm
+}
+
+func isCodeRune(c rune) bool {
+	return c < unicode.MaxASCII && (unicode.IsLetter(c) || unicode.IsDigit(c))
+}
+
+func start(m tea.Model) (tea.Model, tea.Cmd) {
+	return m, m.Init()
+}
+
+// start opens m with the window size the lobby last saw.
+func (lm LobbyModel) start(m tea.Model) (tea.Model, tea.Cmd) {
+	return startSized(m, lm.size)
+}
+
+// startSized opens m and tells it the window size, which Bubble Tea only
+// sends when the program starts and on resizes.
+func startSized(m tea.Model, size tea.WindowSizeMsg) (tea.Model, tea.Cmd) {
+	if size.Width == 0 {
+		return start(m)
+	}
+	return m, tea.Batch(m.Init(), func() tea.Msg { return size })
+}
+
+func (lm LobbyModel) renderWatchable(rendered *strings.Builder) {
+	sessions := lm.watchable()
+	if len(sessions) == 0 {
+		rendered.WriteString("Nobody else is playing right now.\n")
+	}
+
+	for i, s := range sessions {
+		prefix := "  "
+		if i == lm.watched {
+			prefix = "> "
+		}
+
+		state := "Playing"
+		switch s.Game.State() {
+		case game.StateWon:
+			state = "Won"
+		case game.StateLost:
+			state = "Lost"
+		}
+
+		fmt.Fprintf(
+			rendered,
+			"%s%-12s %dx%d %-7s %ds\n",
+			prefix,
+			s.Player,
+			s.Game.GetGridWidth(),
+			s.Game.GetGridHeight(),
+			state,
+			int(s.Game.Stats().Elapsed.Seconds()),
+		)
+	}
+
+	rendered.WriteString("\nEnter: Watch\n")
+	rendered.WriteString("Esc: Back\n")
+}
+
+func (lm LobbyModel) View() string {
+	rendered := &strings.Builder{}
+
+	rendered.WriteString("Minesshweeper\n")
+	rendered.WriteString("Hello, " + lm.player.Name + "!\n\n")
+
+	if lm.watching {
+		lm.renderWatchable(rendered)
+	} else if lm.joining != "" {
+		label := "Room code: "
+		if lm.joining == "Join race" {
+			label = "Race code: "
+		}
+		rendered.WriteString(label + lm.code + "_\n\n")
+		rendered.WriteString("Enter: Join\n")
+		rendered.WriteString("Esc: Back\n")
+	} else {
+		for i, item := range lobbyItems {
+			if i == lm.selected {
+				rendered.WriteString("> " + item + "\n")
+			} else {
+				rendered.WriteString("  " + item + "\n")
+			}
+		}
+		rendered.WriteString("\n" + pairHint(lm.settings.Keys.Up, lm.settings.Keys.Down, "Move Around") + "\n")
+		rendered.WriteString("Enter: Select\n")
+		rendered.WriteString(keyHint(lm.settings.Keys.Quit, "Quit") + "\n")
+	}
+
+	if lm.err != nil {
+		rendered.WriteString("\nError: " + lm.err.Error() + "\n")
+	}
+
+	return rendered.String()
+}
@@ -0,0 +1,83 @@
+package tui_test
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jboewer/minesshweeper/game"
+	"github.com/jboewer/minesshweeper/leaderboard"
+	"github.com/jboewer/minesshweeper/room"
+	"github.com/jboewer/minesshweeper/spectate"
+	"github.com/jboewer/minesshweeper/tui"
+	"github.com/jboewer/minesshweeper/versus"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestLobbyModel_PlaySeed(t *testing.T) {
+	store := leaderboard.NewMemoryStore()
+	services := &tui.Services{Results: store, Sessions: spectate.NewRegistry()}
+	lobby := tui.NewLobbyModel(services, tui.Player{SessionID: "s", ProfileID: "p"}, tui.DefaultSettings(lipgloss.NewRenderer(io.Discard)))
+
+	m, err := lobby.PlaySeed(game.DifficultyOf(2, 1, 1), 7)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), m.(tui.GameModel).Game.Seed())
+
+	press(m, " ")
+	results, _ := store.Results()
+	assert.Empty(t, results)
+
+	assert.Len(t, services.Sessions.List(), 1)
+	press(m, "q")
+	assert.Empty(t, services.Sessions.List())
+}
+
+func TestLobbyModel_LeaveRace(t *testing.T) {
+	services := &tui.Services{
+		Matches:  versus.NewRegistry(),
+		Sessions: spectate.NewRegistry(),
+		Race:     versus.Settings{Width: 9, Height: 9, Mines: 10},
+	}
+	lobby := tui.NewLobbyModel(services, tui.Player{SessionID: "s", Name: "alice"}, tui.DefaultSettings(lipgloss.NewRenderer(io.Discard)))
+
+	m := press(lobby, "j", "j", "j", "enter")
+	assert.IsType(t, tui.VersusModel{}, m)
+	code := strings.Fields(m.View())[1]
+
+	assert.IsType(t, tui.LobbyModel{}, press(m, "q"))
+	assert.Empty(t, services.Sessions.List())
+	_, _, err := services.Matches.Join(code, "b", "bob")
+	assert.ErrorIs(t, err, versus.ErrMatchNotFound)
+}
+
+func TestLobbyModel_LeaveRoom(t *testing.T) {
+	services := &tui.Services{
+		Rooms:      room.NewRegistry(),
+		Sessions:   spectate.NewRegistry(),
+		Difficulty: game.Beginner,
+	}
+	lobby := tui.NewLobbyModel(services, tui.Player{SessionID: "s", Name: "alice"}, tui.DefaultSettings(lipgloss.NewRenderer(io.Discard)))
+
+	m := press(lobby, "j", "enter")
+	assert.IsType(t, tui.GameModel{}, m)
+	assert.Len(t, services.Rooms.Rooms(), 1)
+
+	assert.IsType(t, tui.LobbyModel{}, press(m, "q"))
+	assert.Empty(t, services.Rooms.Rooms())
+	assert.Empty(t, services.Sessions.List())
+}
+
+func TestLobbyModel_StopWatching(t *testing.T) {
+	services := &tui.Services{Sessions: spectate.NewRegistry()}
+	g, err := game.NewWithDifficulty(game.Beginner)
+	assert.NoError(t, err)
+	s := services.Sessions.Publish("other", "bob", g)
+	lobby := tui.NewLobbyModel(services, tui.Player{SessionID: "s", Name: "alice"}, tui.DefaultSettings(lipgloss.NewRenderer(io.Discard)))
+
+	m := press(lobby, "j", "j", "j", "j", "j", "j", "enter", "enter")
+	assert.IsType(t, tui.SpectatorModel{}, m)
+	assert.Equal(t, 1, s.Watchers())
+
+	assert.IsType(t, tui.LobbyModel{}, press(m, "q"))
+	assert.Zero(t, s.Watchers())
+}
@@ -0,0 +1,286 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jboewer/minesshweeper/game"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// customFieldLength fits the mines of the largest custom board.
+const customFieldLength = 4
+
+var (
+	menuItems = []string{
+		"Beginner",
+		"Intermediate",
+		"Expert",
+		"Custom",
+		"Stats",
+		"Settings",
+		"Quit",
+	}
+	// boardItems are the items of the menu opened from the lobby, which
+	// has its own leaderboards and profile.
+	boardItems = []string{
+		"Beginner",
+		"Intermediate",
+		"Expert",
+		"Custom",
+		"Back",
+	}
+	customFields = []string{"Width", "Height", "Mines"}
+)
+
+// NewMenuModel returns the main menu, where the player picks a board to
+// play alone. Finished games of players with a profile are recorded for
+// the stats.
+func NewMenuModel(services *Services, player Player, settings Settings) MenuModel {
+	return MenuModel{
+		services: services,
+		player:   player,
+		settings: settings,
+		items:    menuItems,
+		custom:   [3]string{"10", "10", "10"},
+	}
+}
+
+type MenuModel struct {
+	services *Services
+	player   Player
+	settings Settings
+	items    []string
+	selected int
+	// editing is set while the player fills in the custom board, field by
+	// field.
+	editing bool
+	custom  [3]string
+	field   int
+	err     error
+	// play starts the game on the board the player picked. Without it, the
+	// game is only recorded.
+	play func(d game.Difficulty) (GameModel, error)
+	// back is the screen the menu was opened from. Without one, leaving the
+	// menu quits.
+	back tea.Model
+	size tea.WindowSizeMsg
+}
+
+func (mm MenuModel) Init() tea.Cmd {
+	return nil
+}
+
+func (mm MenuModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if size, ok := msg.(tea.WindowSizeMsg); ok {
+		mm.size = size
+		return mm, nil
+	}
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return mm, nil
+	}
+
+	if keyMsg.Type == tea.KeyCtrlC {
+		return mm, tea.Quit
+	}
+
+	if mm.editing {
+		return mm.updateCustom(keyMsg)
+	}
+
+	keys := mm.settings.Keys
+	switch {
+	case isBack(keyMsg, keys):
+		return mm.leave()
+	case key.Matches(keyMsg, keys.Up):
+		if mm.selected > 0 {
+			mm.selected--
+		}
+	case key.Matches(keyMsg, keys.Down):
+		if mm.selected < len(mm.items)-1 {
+			mm.selected++
+		}
+	case isSelect(keyMsg):
+		return mm.choose()
+	}
+
+	return mm, nil
+}
+
+func (mm MenuModel) choose() (tea.Model, tea.Cmd) {
+	mm.err = nil
+
+	switch item := mm.items[mm.selected]; item {
+	case "Custom":
+		mm.editing = true
+		mm.field = 0
+	case "Stats":
+		return startSized(NewStatsModel(mm.services, mm.player, mm, mm.settings), mm.size)
+	case "Settings":
+		return startSized(NewSettingsModel(mm.services, mm), mm.size)
+	case "Quit", "Back":
+		return mm.leave()
+	default:
+		d, err := game.ParseDifficulty(item)
+		if err != nil {
+			mm.err = err
+			return mm, nil
+		}
+		return mm.start(d)
+	}
+
+	return mm, nil
+}
+
+// start plays a game on d, which returns to the menu once it is left.
+func (mm MenuModel) start(d game.Difficulty) (tea.Model, tea.Cmd) {
+	play := mm.play
+	if play == nil {
+		play = mm.playAlone
+	}
+
+	m, err := play(d)
+	if err != nil {
+		mm.err = err
+		return mm, nil
+	}
+	mm.editing = false
+	m.back = mm
+	return startSized(m, mm.size)
+}
+
+func (mm MenuModel) playAlone(d game.Difficulty) (GameModel, error) {
+	g, err := game.NewWithDifficulty(d)
+	if err != nil {
+		return GameModel{}, err
+	}
+	return recorded(NewGameModel(g, mm.settings), mm.services.Results, mm.player, ""), nil
+}
+
+func (mm MenuModel) leave() (tea.Model, tea.Cmd) {
+	if mm.back == nil {
+		return mm, tea.Quit
+	}
+	return startSized(mm.back, mm.size)
+}
+
+// prefer selects d, filling in the custom board if it isn't a preset.
+func (mm *MenuModel) prefer(d game.Difficulty) {
+	for i, item := range mm.items {
+		if item == d.Name {
+			mm.selected = i
+			return
+		}
+	}
+
+	for i, item := range mm.items {
+		if item == "Custom" {
+			mm.selected = i
+			mm.custom = [3]string{strconv.Itoa(d.Width), strconv.Itoa(d.Height), strconv.Itoa(d.Mines)}
+		}
+	}
+}
+
+func (mm MenuModel) updateCustom(key tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch key.String() {
+	case "esc":
+		mm.editing = false
+		mm.err = nil
+	case "tab", "down":
+		mm.field = (mm.field + 1) % len(customFields)
+	case "shift+tab", "up":
+		mm.field = (mm.field + len(customFields) - 1) % len(customFields)
+	case "enter":
+		d, err := parseCustom(mm.custom)
+		if err != nil {
+			mm.err = err
+			return mm, nil
+		}
+		mm.err = nil
+		return mm.start(d)
+	default:
+		mm.custom[mm.field] = editLine(mm.custom[mm.field], key, customFieldLength, isDigitRune)
+	}
+
+	return mm, nil
+}
+
+// parseCustom checks the fields of the custom board form.
+func parseCustom(fields [3]string) (game.Difficulty, error) {
+	var n [3]int
+	for i, field := range fields {
+		v, err := strconv.Atoi(field)
+		if err != nil {
+			return game.Difficulty{}, fmt.Errorf("%s must be a number", strings.ToLower(customFields[i]))
+		}
+		n[i] = v
+	}
+
+	width, height, mines := n[0], n[1], n[2]
+	if width < 1 || width > game.MaxSide {
+		return game.Difficulty{}, fmt.Errorf("width must be between 1 and %d", game.MaxSide)
+	}
+	if height < 1 || height > game.MaxSide {
+		return game.Difficulty{}, fmt.Errorf("height must be between 1 and %d", game.MaxSide)
+	}
+	if width*height < 2 {
+		return game.Difficulty{}, errors.New("a board needs at least 2 cells")
+	}
+	if mines < 1 || mines >= width*height {
+		return game.Difficulty{}, fmt.Errorf("mines must be between 1 and %d on a %dx%d board", width*height-1, width, height)
+	}
+
+	d := game.DifficultyOf(width, height, mines)
+	return d, d.Validate()
+}
+
+func isDigitRune(c rune) bool {
+	return c < unicode.MaxASCII && unicode.IsDigit(c)
+}
+
+func (mm MenuModel) View() string {
+	rendered := &strings.Builder{}
+
+	rendered.WriteString("Minesshweeper\n")
+	rendered.WriteString("Pick a board\n\n")
+
+	if mm.editing {
+		for i, name := range customFields {
+			prefix, cursor := "  ", ""
+			if i == mm.field {
+				prefix, cursor = "> ", "_"
+			}
+			fmt.Fprintf(rendered, "%s%-7s %s%s\n", prefix, name+":", mm.custom[i], cursor)
+		}
+		rendered.WriteString("\nTab: Next Field\n")
+		rendered.WriteString("Enter: Play\n")
+		rendered.WriteString("Esc: Back\n")
+	} else {
+		for i, item := range mm.items {
+			prefix := "  "
+			if i == mm.selected {
+				prefix = "> "
+			}
+
+			d, err := game.ParseDifficulty(item)
+			if err != nil {
+				rendered.WriteString(prefix + item + "\n")
+				continue
+			}
+			fmt.Fprintf(rendered, "%s%-13s %dx%d, %d mines\n", prefix, item, d.Width, d.Height, d.Mines)
+		}
+		rendered.WriteString("\n" + pairHint(mm.settings.Keys.Up, mm.settings.Keys.Down, "Move Around") + "\n")
+		rendered.WriteString("Enter: Select\n")
+	}
+
+	if mm.err != nil {
+		rendered.WriteString("\nError: " + mm.err.Error() + "\n")
+	}
+
+	return rendered.String()
+}
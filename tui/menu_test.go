@@ -0,0 +1,85 @@
+package tui_test
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jboewer/minesshweeper/leaderboard"
+	"github.com/jboewer/minesshweeper/tui"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"testing"
+)
+
+func press(m tea.Model, keys ...string) tea.Model {
+	for _, k := range keys {
+		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+		switch k {
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		case "tab":
+			msg = tea.KeyMsg{Type: tea.KeyTab}
+		case "backspace":
+			msg = tea.KeyMsg{Type: tea.KeyBackspace}
+		}
+		m, _ = m.Update(msg)
+	}
+	return m
+}
+
+func TestMenuModel(t *testing.T) {
+	services := &tui.Services{Results: leaderboard.NewMemoryStore()}
+	menu := tui.NewMenuModel(services, tui.Player{ProfileID: "p"}, tui.DefaultSettings(lipgloss.NewRenderer(io.Discard)))
+
+	t.Run("Presets", func(t *testing.T) {
+		m := press(menu, "j", "enter")
+
+		if assert.IsType(t, tui.GameModel{}, m) {
+			g := m.(tui.GameModel).Game
+			assert.Equal(t, 16, g.GetGridWidth())
+			assert.Equal(t, 40, g.GetMineCount())
+		}
+		assert.IsType(t, tui.MenuModel{}, press(m, "q"))
+	})
+	t.Run("Custom", func(t *testing.T) {
+		m := press(menu, "j", "j", "j", "enter", "backspace", "backspace", "2", "0", "tab", "tab", "backspace", "backspace", "999", "enter")
+
+		assert.IsType(t, tui.MenuModel{}, m)
+		assert.Contains(t, m.View(), "mines must be between 1 and 199 on a 20x10 board")
+
+		m = press(m, "backspace", "enter")
+
+		if assert.IsType(t, tui.GameModel{}, m) {
+			g := m.(tui.GameModel).Game
+			assert.Equal(t, 20, g.GetGridWidth())
+			assert.Equal(t, 99, g.GetMineCount())
+		}
+	})
+	t.Run("Games on a seed are not recorded", func(t *testing.T) {
+		store := leaderboard.NewMemoryStore()
+		menu := tui.NewMenuModel(&tui.Services{Results: store}, tui.Player{ProfileID: "p"}, tui.DefaultSettings(lipgloss.NewRenderer(io.Discard)))
+		// A 2x1 board with one mine ends on the first reveal.
+		tiny := []string{"j", "j", "j", "enter", "backspace", "backspace", "2", "tab", "backspace", "backspace", "1", "tab", "backspace", "backspace", "1", "enter"}
+
+		press(menu, append(tiny, " ")...)
+		results, _ := store.Results()
+		assert.Len(t, results, 1)
+
+		press(menu, append(tiny, "S", "5", "enter", " ")...)
+		results, _ = store.Results()
+		assert.Len(t, results, 1)
+	})
+	t.Run("Player's keys", func(t *testing.T) {
+		settings := tui.DefaultSettings(lipgloss.NewRenderer(io.Discard))
+		settings.Keys = tui.AZERTYKeys
+		menu := tui.NewMenuModel(services, tui.Player{ProfileID: "p"}, settings)
+
+		m := press(menu, "s", "s", "z", "q")
+		assert.IsType(t, tui.MenuModel{}, m)
+		assert.Contains(t, m.View(), "ZS: Move Around")
+
+		m = press(m, "enter")
+		if assert.IsType(t, tui.GameModel{}, m) {
+			assert.Equal(t, 16, m.(tui.GameModel).Game.GetGridWidth())
+		}
+	})
+}
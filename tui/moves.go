@@ -0,0 +1,221 @@
+package tui
+
+import (
+	"github.com/jboewer/minesshweeper/game"
+	"github.com/jboewer/minesshweeper/leaderboard"
+	"github.com/jboewer/minesshweeper/room"
+	"github.com/jboewer/minesshweeper/spectate"
+	"github.com/jboewer/minesshweeper/versus"
+	"log"
+)
+
+// moves is where GameModel sends the player's moves. Shared sessions use it
+// to pass them on to the other players.
+type moves interface {
+	Reveal(x, y int)
+	Chord(x, y int)
+	ToggleFlag(x, y int)
+	MoveCursor(x, y int)
+	Reset()
+	Restart()
+	Reseed(seed int64)
+}
+
+type soloMoves struct {
+	game *game.Game
+}
+
+func (m soloMoves) Reveal(x, y int) {
+	m.game.Reveal(x, y)
+}
+
+func (m soloMoves) Chord(x, y int) {
+	m.game.Chord(x, y)
+}
+
+func (m soloMoves) ToggleFlag(x, y int) {
+	m.game.ToggleFlag(x, y)
+}
+
+func (m soloMoves) MoveCursor(x, y int) {}
+
+func (m soloMoves) Reset() {
+	m.game.Reset()
+}
+
+func (m soloMoves) Restart() {
+	m.game.Restart()
+}
+
+func (m soloMoves) Reseed(seed int64) {
+	m.game.Reseed(seed)
+}
+
+type roomMoves struct {
+	room     *room.Room
+	playerID string
+}
+
+func (m roomMoves) Reveal(x, y int) {
+	m.room.Reveal(m.playerID, x, y)
+}
+
+func (m roomMoves) Chord(x, y int) {
+	m.room.Chord(m.playerID, x, y)
+}
+
+func (m roomMoves) ToggleFlag(x, y int) {
+	m.room.ToggleFlag(m.playerID, x, y)
+}
+
+func (m roomMoves) MoveCursor(x, y int) {
+	m.room.MoveCursor(m.playerID, x, y)
+}
+
+func (m roomMoves) Reset() {
+	m.room.Reset(m.playerID)
+}
+
+func (m roomMoves) Restart() {
+	m.room.Restart(m.playerID)
+}
+
+func (m roomMoves) Reseed(seed int64) {
+	m.room.Reseed(m.playerID, seed)
+}
+
+// raceMoves can't reset, since every racer has to finish the board they
+// started with.
+type raceMoves struct {
+	match    *versus.Match
+	playerID string
+}
+
+func (m raceMoves) Reveal(x, y int) {
+	m.match.Reveal(m.playerID, x, y)
+}
+
+func (m raceMoves) Chord(x, y int) {
+	m.match.Chord(m.playerID, x, y)
+}
+
+func (m raceMoves) ToggleFlag(x, y int) {
+	m.match.ToggleFlag(m.playerID, x, y)
+}
+
+func (m raceMoves) MoveCursor(x, y int) {}
+
+func (m raceMoves) Reset() {}
+
+func (m raceMoves) Restart() {}
+
+func (m raceMoves) Reseed(seed int64) {}
+
+// challengeMoves can't reset, since the daily challenge has a single board.
+type challengeMoves struct {
+	soloMoves
+}
+
+func (m challengeMoves) Reset() {}
+
+func (m challengeMoves) Restart() {}
+
+func (m challengeMoves) Reseed(seed int64) {}
+
+// watchedMoves tells the spectators of a session about every move.
+type watchedMoves struct {
+	moves
+	session *spectate.Session
+}
+
+func (m watchedMoves) Reveal(x, y int) {
+	m.moves.Reveal(x, y)
+	m.session.Changed()
+}
+
+func (m watchedMoves) Chord(x, y int) {
+	m.moves.Chord(x, y)
+	m.session.Changed()
+}
+
+func (m watchedMoves) ToggleFlag(x, y int) {
+	m.moves.ToggleFlag(x, y)
+	m.session.Changed()
+}
+
+func (m watchedMoves) MoveCursor(x, y int) {
+	m.moves.MoveCursor(x, y)
+	m.session.MoveCursor(x, y)
+}
+
+func (m watchedMoves) Reset() {
+	m.moves.Reset()
+	m.session.Changed()
+}
+
+func (m watchedMoves) Restart() {
+	m.moves.Restart()
+	m.session.Changed()
+}
+
+func (m watchedMoves) Reseed(seed int64) {
+	m.moves.Reseed(seed)
+	m.session.Changed()
+}
+
+// recordedMoves saves the game to the leaderboard once it ends.
+type recordedMoves struct {
+	moves
+	game     *game.Game
+	store    leaderboard.Store
+	player   Player
+	recorded *bool
+	// daily is the date of the daily challenge the game is a ranked
+	// attempt at, if any.
+	daily string
+}
+
+func (m recordedMoves) Reveal(x, y int) {
+	m.moves.Reveal(x, y)
+	m.record()
+}
+
+func (m recordedMoves) Chord(x, y int) {
+	m.moves.Chord(x, y)
+	m.record()
+}
+
+func (m recordedMoves) ToggleFlag(x, y int) {
+	m.moves.ToggleFlag(x, y)
+	m.record()
+}
+
+func (m recordedMoves) Reset() {
+	m.moves.Reset()
+	*m.recorded = false
+}
+
+// Restart and Reseed don't record the next game, since the player may
+// already know where the mines are.
+func (m recordedMoves) Restart() {
+	m.moves.Restart()
+	*m.recorded = true
+}
+
+func (m recordedMoves) Reseed(seed int64) {
+	m.moves.Reseed(seed)
+	*m.recorded = true
+}
+
+func (m recordedMoves) record() {
+	if *m.recorded || m.game.State() == game.StatePlaying {
+		return
+	}
+	*m.recorded = true
+
+	r := leaderboard.NewResult(m.player.ProfileID, m.player.Name, m.game)
+	r.Daily = m.daily
+	if err := m.store.Record(r); err != nil {
+		log.Println("Could not record result:", err)
+	}
+}
@@ -0,0 +1,55 @@
+package tui
+
+import (
+	"github.com/mattn/go-runewidth"
+	"github.com/muesli/ansi"
+	"github.com/muesli/reflow/truncate"
+	"strings"
+)
+
+const resetStyle = "\x1b[0m"
+
+// overlay draws box over background with its top left corner at column x
+// of line y. Lines of the box that would go below the background are left
+// out. Both may be styled.
+func overlay(background, box string, x, y int) string {
+	lines := strings.Split(background, "\n")
+	for i, line := range strings.Split(box, "\n") {
+		if y+i >= len(lines) {
+			break
+		}
+
+		behind := lines[y+i]
+		left := truncate.String(behind, uint(x))
+		if pad := x - ansi.PrintableRuneWidth(left); pad > 0 {
+			left += strings.Repeat(" ", pad)
+		}
+		lines[y+i] = left + resetStyle + line + skipColumns(behind, x+ansi.PrintableRuneWidth(line))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// skipColumns cuts the first n columns off s. The escape sequences in the
+// part that is cut are kept, so the rest is styled as before.
+func skipColumns(s string, n int) string {
+	var escapes strings.Builder
+	inEscape := false
+	width := 0
+
+	for i, c := range s {
+		switch {
+		case c == ansi.Marker:
+			inEscape = true
+			escapes.WriteRune(c)
+		case inEscape:
+			escapes.WriteRune(c)
+			inEscape = !ansi.IsTerminator(c)
+		case width >= n:
+			return escapes.String() + s[i:]
+		default:
+			width += runewidth.RuneWidth(c)
+		}
+	}
+
+	return escapes.String()
+}
@@ -0,0 +1,325 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jboewer/minesshweeper/game"
+	"github.com/jboewer/minesshweeper/profile"
+	"strings"
+	"unicode"
+)
+
+const nicknameLength = 16
+
+var errNoProfile = errors.New("connect with an SSH key to get a profile")
+
+// NewProfileSetupModel asks a player with an unknown key for a nickname, or
+// for a link code to add the key to a profile they already have.
+func NewProfileSetupModel(services *Services, player Player, settings Settings) ProfileSetupModel {
+	return ProfileSetupModel{
+		services: services,
+		player:   player,
+		settings: settings,
+	}
+}
+
+type ProfileSetupModel struct {
+	services *Services
+	player   Player
+	settings Settings
+	size     tea.WindowSizeMsg
+	linking  bool
+	input    string
+	err      error
+}
+
+func (pm ProfileSetupModel) Init() tea.Cmd {
+	return nil
+}
+
+func (pm ProfileSetupModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if size, ok := msg.(tea.WindowSizeMsg); ok {
+		pm.size = size
+		return pm, nil
+	}
+
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return pm, nil
+	}
+
+	switch key.Type {
+	case tea.KeyCtrlC, tea.KeyEsc:
+		return pm, tea.Quit
+	case tea.KeyTab:
+		pm.linking = !pm.linking
+		pm.input = ""
+		pm.err = nil
+	case tea.KeyEnter:
+		p, err := pm.submit()
+		if err != nil {
+			pm.err = err
+			return pm, nil
+		}
+
+		pm.player.ProfileID = p.ID
+		pm.player.Name = p.Name
+		lobby := NewLobbyModel(pm.services, pm.player, pm.settings)
+		lobby.size = pm.size
+		return start(lobby)
+	default:
+		if pm.linking {
+			pm.input = strings.ToUpper(editLine(pm.input, key, profile.LinkCodeLength, isCodeRune))
+		} else {
+			pm.input = editLine(pm.input, key, nicknameLength, isNicknameRune)
+		}
+	}
+
+	return pm, nil
+}
+
+func (pm ProfileSetupModel) submit() (profile.Profile, error) {
+	if pm.linking {
+		id, err := pm.services.LinkCodes.Redeem(pm.input, pm.player.Address)
+		if err != nil {
+			return profile.Profile{}, err
+		}
+		return profile.LinkKey(pm.services.Profiles, id, pm.player.KeyID)
+	}
+
+	p, err := profile.New(pm.input, pm.player.KeyID)
+	if err != nil {
+		return profile.Profile{}, err
+	}
+	return p, pm.services.Profiles.Save(p)
+}
+
+func (pm ProfileSetupModel) View() string {
+	rendered := &strings.Builder{}
+
+	rendered.WriteString("Welcome to Minesshweeper!\n\n")
+
+	if pm.linking {
+		rendered.WriteString("Enter the link code shown in your profile on another key.\n\n")
+		rendered.WriteString("Link code: " + pm.input + "_\n\n")
+		rendered.WriteString("Enter: Link Key\n")
+		rendered.WriteString("Tab: Pick a Nickname Instead\n")
+	} else {
+		rendered.WriteString("Your key is new here. Pick a nickname for the leaderboards.\n\n")
+		rendered.WriteString("Nickname: " + pm.input + "_\n\n")
+		rendered.WriteString("Enter: Save\n")
+		rendered.WriteString("Tab: Link to an Existing Profile\n")
+	}
+	rendered.WriteString("Esc: Quit\n")
+
+	if pm.err != nil {
+		rendered.WriteString("\nError: " + pm.err.Error() + "\n")
+	}
+
+	return rendered.String()
+}
+
+func isNicknameRune(c rune) bool {
+	return c < unicode.MaxASCII && (unicode.IsLetter(c) || unicode.IsDigit(c) || c == '-' || c == '_')
+}
+
+// NewProfileModel shows the player's profile and lets them change it.
+// Leaving the profile returns to the lobby.
+func NewProfileModel(services *Services, back LobbyModel) ProfileModel {
+	return ProfileModel{
+		services: services,
+		back:     back,
+	}
+}
+
+type ProfileModel struct {
+	services *Services
+	back     LobbyModel
+	renaming bool
+	input    string
+	linkCode string
+	err      error
+}
+
+func (pm ProfileModel) Init() tea.Cmd {
+	return nil
+}
+
+func (pm ProfileModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if size, ok := msg.(tea.WindowSizeMsg); ok {
+		pm.back.size = size
+		return pm, nil
+	}
+
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return pm, nil
+	}
+
+	if key.Type == tea.KeyCtrlC {
+		return pm, tea.Quit
+	}
+
+	if pm.renaming {
+		return pm.updateRenaming(key)
+	}
+
+	pm.err = nil
+
+	if isBack(key, pm.back.settings.Keys) {
+		return pm.back, nil
+	}
+
+	switch key.String() {
+	case "n":
+		pm.renaming = true
+		pm.input = ""
+	case "l":
+		pm.linkCode = pm.services.LinkCodes.Issue(pm.back.player.ProfileID)
+	case "d":
+		pm.err = pm.update(func(p *profile.Profile) {
+			p.Difficulty = nextDifficulty(p.Difficulty)
+		})
+	case "t":
+		var theme string
+		pm.err = pm.update(func(p *profile.Profile) {
+			theme = nextName(themeNames(pm.services.themes()), p.Theme)
+			p.Theme = theme
+		})
+		if pm.err == nil {
+			pm.back.settings.Theme = pm.services.Theme(theme)
+		}
+	case "k":
+		var keys string
+		pm.err = pm.update(func(p *profile.Profile) {
+			keys = nextName(keyMapNames(pm.services.keyMaps()), p.KeyBindings)
+			p.KeyBindings = keys
+		})
+		if pm.err == nil {
+			pm.back.settings.Keys = pm.services.KeyMap(keys)
+		}
+	}
+
+	return pm, nil
+}
+
+func (pm ProfileModel) updateRenaming(key tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch key.Type {
+	case tea.KeyEsc:
+		pm.renaming = false
+	case tea.KeyEnter:
+		name := pm.input
+		pm.err = pm.update(func(p *profile.Profile) {
+			p.Name = name
+		})
+		if pm.err == nil {
+			pm.renaming = false
+			pm.back.player.Name = name
+		}
+	default:
+		pm.input = editLine(pm.input, key, nicknameLength, isNicknameRune)
+	}
+
+	return pm, nil
+}
+
+func (pm ProfileModel) update(change func(p *profile.Profile)) error {
+	p, err := pm.services.Profiles.Get(pm.back.player.ProfileID)
+	if err != nil {
+		return err
+	}
+
+	change(&p)
+	return pm.services.Profiles.Save(p)
+}
+
+// nextDifficulty cycles through the presets, starting with the server's
+// default board.
+func nextDifficulty(name string) string {
+	for i, d := range game.Difficulties {
+		if d.Name == name {
+			if i == len(game.Difficulties)-1 {
+				return ""
+			}
+			return game.Difficulties[i+1].Name
+		}
+	}
+	return game.Difficulties[0].Name
+}
+
+// nextName cycles through the names of themes or key maps, starting with
+// the first, which is the default.
+func nextName(names []string, name string) string {
+	for i, n := range names {
+		if strings.EqualFold(n, name) {
+			return names[(i+1)%len(names)]
+		}
+	}
+	return names[min(1, len(names)-1)]
+}
+
+func themeNames(themes []Theme) []string {
+	names := make([]string, len(themes))
+	for i, t := range themes {
+		names[i] = t.Name
+	}
+	return names
+}
+
+func keyMapNames(keyMaps []KeyMap) []string {
+	names := make([]string, len(keyMaps))
+	for i, k := range keyMaps {
+		names[i] = k.Name
+	}
+	return names
+}
+
+func (pm ProfileModel) View() string {
+	rendered := &strings.Builder{}
+
+	p, err := pm.services.Profiles.Get(pm.back.player.ProfileID)
+	if err != nil {
+		return "Error: " + err.Error() + "\n"
+	}
+
+	rendered.WriteString("Profile\n\n")
+
+	if pm.renaming {
+		rendered.WriteString("New nickname: " + pm.input + "_\n\n")
+		rendered.WriteString("Enter: Save\n")
+		rendered.WriteString("Esc: Cancel\n")
+	} else {
+		fmt.Fprintf(rendered, "Nickname:    %s\n", p.Name)
+		fmt.Fprintf(rendered, "Difficulty:  %s\n", orDefault(p.Difficulty))
+		fmt.Fprintf(rendered, "Theme:       %s\n", pm.services.Theme(p.Theme).Name)
+		fmt.Fprintf(rendered, "Keybindings: %s\n", pm.services.KeyMap(p.KeyBindings).Name)
+		fmt.Fprintf(rendered, "Keys:        %d\n", len(p.Keys))
+		fmt.Fprintf(rendered, "Member since %s\n", p.CreatedAt.Format("2006-01-02"))
+
+		if pm.linkCode != "" {
+			rendered.WriteString("\nTo add another key, connect with it and enter the link code " + pm.linkCode + ".\n")
+			rendered.WriteString("The code is valid for 10 minutes.\n")
+		}
+
+		rendered.WriteString("\nN: Change Nickname\n")
+		rendered.WriteString("D: Change Difficulty\n")
+		rendered.WriteString("T: Change Theme\n")
+		rendered.WriteString("K: Change Keybindings\n")
+		rendered.WriteString("L: Link Another Key\n")
+		rendered.WriteString("Esc: Back\n")
+	}
+
+	if pm.err != nil {
+		rendered.WriteString("\nError: " + pm.err.Error() + "\n")
+	}
+
+	return rendered.String()
+}
+
+func orDefault(s string) string {
+	if s == "" {
+		return "Default"
+	}
+	return s
+}
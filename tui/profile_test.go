@@ -0,0 +1,25 @@
+package tui_test
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jboewer/minesshweeper/profile"
+	"github.com/jboewer/minesshweeper/tui"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"testing"
+)
+
+func TestProfileSetupModel_LinkAttempts(t *testing.T) {
+	services := &tui.Services{Profiles: profile.NewMemoryStore(), LinkCodes: profile.NewLinkCodes()}
+	code := services.LinkCodes.Issue("alice")
+	m := press(tui.NewProfileSetupModel(services, tui.Player{KeyID: "key-m", Address: "10.0.0.66"}, tui.DefaultSettings(lipgloss.NewRenderer(io.Discard))), "tab")
+
+	for i := 0; i < 5; i++ {
+		m = press(m, "WRONG", "enter")
+		assert.Contains(t, m.View(), profile.ErrInvalidLinkCode.Error())
+		m = press(m, "backspace", "backspace", "backspace", "backspace", "backspace")
+	}
+
+	m = press(m, code, "enter")
+	assert.Contains(t, m.View(), profile.ErrTooManyLinkCodes.Error())
+}
@@ -0,0 +1,98 @@
+package tui
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jboewer/minesshweeper/daily"
+	"github.com/jboewer/minesshweeper/game"
+	"github.com/jboewer/minesshweeper/leaderboard"
+	"github.com/jboewer/minesshweeper/profile"
+	"github.com/jboewer/minesshweeper/room"
+	"github.com/jboewer/minesshweeper/spectate"
+	"github.com/jboewer/minesshweeper/versus"
+)
+
+// Services holds the server-side state that is shared between the sessions
+// of a multiplayer server.
+type Services struct {
+	Rooms   *room.Registry
+	Matches *versus.Registry
+	// Sessions lists the games that can be watched.
+	Sessions *spectate.Registry
+	// Results keeps the finished games of players with a known key.
+	Results leaderboard.Store
+	// Profiles holds the players known by their SSH keys.
+	Profiles  profile.Store
+	LinkCodes *profile.LinkCodes
+	// Difficulty is the board for players without a preference.
+	Difficulty game.Difficulty
+	// Race is the board used for versus matches.
+	Race versus.Settings
+	// Attempts tracks who has played today's daily challenge.
+	Attempts *daily.Attempts
+	// Themes default to the built-in ones.
+	Themes []Theme
+	// KeyMaps default to the built-in ones.
+	KeyMaps []KeyMap
+}
+
+// Theme returns the named theme, or the classic one.
+func (s *Services) Theme(name string) Theme {
+	if t, ok := FindTheme(s.themes(), name); ok {
+		return t
+	}
+	return Classic
+}
+
+func (s *Services) themes() []Theme {
+	if s.Themes == nil {
+		return Themes
+	}
+	return s.Themes
+}
+
+// KeyMap returns the named key map, or the default one.
+func (s *Services) KeyMap(name string) KeyMap {
+	if k, ok := FindKeyMap(s.keyMaps(), name); ok {
+		return k
+	}
+	return DefaultKeys
+}
+
+func (s *Services) keyMaps() []KeyMap {
+	if s.KeyMaps == nil {
+		return KeyMaps
+	}
+	return s.KeyMaps
+}
+
+// Settings are how a player's screens are drawn.
+type Settings struct {
+	// Renderer draws for the player's terminal.
+	Renderer *lipgloss.Renderer
+	Theme    Theme
+	Keys     KeyMap
+}
+
+// DefaultSettings uses the classic theme and the default keys.
+func DefaultSettings(renderer *lipgloss.Renderer) Settings {
+	return Settings{
+		Renderer: renderer,
+		Theme:    Classic,
+		Keys:     DefaultKeys,
+	}
+}
+
+// Player identifies whoever is connected to a session.
+type Player struct {
+	// SessionID is unique for every connection.
+	SessionID string
+	// KeyID is the fingerprint of the key the player connected with. It is
+	// empty for players without a public key.
+	KeyID string
+	// Address is the host the player connected from.
+	Address string
+	// ProfileID identifies the player across connections. Games are only
+	// recorded for players with a profile.
+	ProfileID string
+	Name      string
+}
@@ -0,0 +1,89 @@
+package tui
+
+import (
+	"fmt"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"strings"
+)
+
+var settingsItems = []string{"Theme", "Keys"}
+
+// NewSettingsModel lets the player pick a theme and keys. Leaving the
+// settings returns to the menu, which plays with them from then on.
+func NewSettingsModel(services *Services, back MenuModel) SettingsModel {
+	return SettingsModel{
+		services: services,
+		back:     back,
+	}
+}
+
+type SettingsModel struct {
+	services *Services
+	back     MenuModel
+	selected int
+}
+
+func (sm SettingsModel) Init() tea.Cmd {
+	return nil
+}
+
+func (sm SettingsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if size, ok := msg.(tea.WindowSizeMsg); ok {
+		sm.back.size = size
+		return sm, nil
+	}
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return sm, nil
+	}
+
+	keys := sm.back.settings.Keys
+	switch {
+	case keyMsg.Type == tea.KeyCtrlC:
+		return sm, tea.Quit
+	case isBack(keyMsg, keys):
+		return sm.back, nil
+	case key.Matches(keyMsg, keys.Up):
+		if sm.selected > 0 {
+			sm.selected--
+		}
+	case key.Matches(keyMsg, keys.Down):
+		if sm.selected < len(settingsItems)-1 {
+			sm.selected++
+		}
+	case isSelect(keyMsg), key.Matches(keyMsg, keys.Right):
+		settings := &sm.back.settings
+		switch settingsItems[sm.selected] {
+		case "Theme":
+			settings.Theme = sm.services.Theme(nextName(themeNames(sm.services.themes()), settings.Theme.Name))
+		case "Keys":
+			settings.Keys = sm.services.KeyMap(nextName(keyMapNames(sm.services.keyMaps()), settings.Keys.Name))
+		}
+	}
+
+	return sm, nil
+}
+
+func (sm SettingsModel) View() string {
+	rendered := &strings.Builder{}
+
+	rendered.WriteString("Settings\n\n")
+
+	values := []string{sm.back.settings.Theme.Name, sm.back.settings.Keys.Name}
+	for i, item := range settingsItems {
+		prefix := "  "
+		if i == sm.selected {
+			prefix = "> "
+		}
+		fmt.Fprintf(rendered, "%s%-6s %s\n", prefix, item+":", values[i])
+	}
+
+	keys := sm.back.settings.Keys
+	rendered.WriteString("\n" + pairHint(keys.Up, keys.Down, "Move Around") + "\n")
+	rendered.WriteString("Enter: Change\n")
+	rendered.WriteString("Esc: Back\n")
+
+	return rendered.String()
+}
@@ -0,0 +1,121 @@
+package tui
+
+import (
+	"fmt"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jboewer/minesshweeper/game"
+	"github.com/jboewer/minesshweeper/spectate"
+	"strings"
+	"time"
+)
+
+// NewSpectatorModel returns a read-only view of someone else's game. It
+// follows the player's cursor and only shows what the player can see.
+// Stopping watching returns to the lobby.
+func NewSpectatorModel(s *spectate.Session, back LobbyModel) SpectatorModel {
+	updates, stop := s.Watch(back.player.SessionID)
+
+	board := newGameModel(s.Game, nil, back.settings)
+	c := s.Cursor()
+	board.Cursor.x, board.Cursor.y = c.X, c.Y
+	// The spectator view has no instructions below the board.
+	board.top, board.bottom = 1, 2
+	board.hideHelp = true
+
+	return SpectatorModel{
+		session: s,
+		back:    back,
+		board:   board,
+		updates: updates,
+		stop:    stop,
+	}
+}
+
+type SpectatorModel struct {
+	session *spectate.Session
+	back    LobbyModel
+	board   GameModel
+	updates <-chan struct{}
+	stop    func()
+	ended   bool
+}
+
+type spectateUpdateMsg struct{}
+
+type spectateEndedMsg struct{}
+
+type spectateTickMsg struct{}
+
+func waitForSpectateUpdate(updates <-chan struct{}) tea.Cmd {
+	return func() tea.Msg {
+		if _, ok := <-updates; !ok {
+			return spectateEndedMsg{}
+		}
+		return spectateUpdateMsg{}
+	}
+}
+
+func spectateTick() tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return spectateTickMsg{}
+	})
+}
+
+func (sm SpectatorModel) Init() tea.Cmd {
+	return tea.Batch(waitForSpectateUpdate(sm.updates), spectateTick())
+}
+
+func (sm SpectatorModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case spectateUpdateMsg:
+		c := sm.session.Cursor()
+		sm.board.Cursor.x, sm.board.Cursor.y = c.X, c.Y
+		sm.board.follow()
+		return sm, waitForSpectateUpdate(sm.updates)
+	case tea.WindowSizeMsg:
+		m, _ := sm.board.Update(msg)
+		sm.board = m.(GameModel)
+	case spectateEndedMsg:
+		sm.ended = true
+	case spectateTickMsg:
+		if !sm.ended {
+			return sm, spectateTick()
+		}
+	case tea.KeyMsg:
+		switch {
+		case msg.Type == tea.KeyCtrlC:
+			sm.stop()
+			return sm, tea.Quit
+		case isBack(msg, sm.back.settings.Keys):
+			sm.stop()
+			return startSized(sm.back, tea.WindowSizeMsg{Width: sm.board.width, Height: sm.board.height})
+		}
+	}
+
+	return sm, nil
+}
+
+func (sm SpectatorModel) View() string {
+	rendered := &strings.Builder{}
+
+	stats := sm.session.Game.Stats()
+	fmt.Fprintf(rendered, "Watching %s - %ds\n", sm.session.Player, int(stats.Elapsed.Seconds()))
+
+	sm.board.renderGameGrid(rendered)
+
+	switch {
+	case sm.ended:
+		rendered.WriteString("The player has left")
+	case sm.session.Game.State() == game.StateWon:
+		rendered.WriteString("Won")
+	case sm.session.Game.State() == game.StateLost:
+		rendered.WriteString("Lost")
+	default:
+		rendered.WriteString("Playing")
+	}
+	rendered.WriteString("\n")
+
+	rendered.WriteString(keyHint(sm.back.settings.Keys.Quit, "Stop Watching") + "\n")
+
+	return rendered.String()
+}
@@ -0,0 +1,100 @@
+package tui
+
+import (
+	"fmt"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+	"github.com/jboewer/minesshweeper/leaderboard"
+	"strings"
+)
+
+// NewStatsModel shows how the player has done on each board. Leaving the
+// stats returns to back.
+func NewStatsModel(services *Services, player Player, back tea.Model, settings Settings) StatsModel {
+	return StatsModel{
+		services: services,
+		player:   player,
+		back:     back,
+		settings: settings,
+	}
+}
+
+type StatsModel struct {
+	services *Services
+	player   Player
+	back     tea.Model
+	settings Settings
+}
+
+func (sm StatsModel) Init() tea.Cmd {
+	return nil
+}
+
+func (sm StatsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if _, ok := msg.(tea.WindowSizeMsg); ok {
+		sm.back, _ = sm.back.Update(msg)
+		return sm, nil
+	}
+
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return sm, nil
+	}
+
+	switch {
+	case key.Type == tea.KeyCtrlC:
+		return sm, tea.Quit
+	case isBack(key, sm.settings.Keys), key.Type == tea.KeyEnter:
+		return sm.back, nil
+	}
+
+	return sm, nil
+}
+
+func (sm StatsModel) View() string {
+	rendered := &strings.Builder{}
+
+	rendered.WriteString("Stats\n\n")
+
+	if sm.player.ProfileID == "" || sm.services.Results == nil {
+		rendered.WriteString(errNoProfile.Error() + ".\n")
+		rendered.WriteString("\nEsc: Back\n")
+		return rendered.String()
+	}
+
+	results, err := sm.services.Results.Results()
+	if err != nil {
+		rendered.WriteString("Error: " + err.Error() + "\n")
+		return rendered.String()
+	}
+
+	rows := [][]string{}
+	for _, r := range leaderboard.RecordsOf(results, sm.player.ProfileID) {
+		best := "-"
+		if r.Best > 0 {
+			best = fmt.Sprintf("%.1fs", r.Best.Seconds())
+		}
+		rows = append(rows, []string{
+			r.Difficulty.Name,
+			fmt.Sprint(r.Played),
+			fmt.Sprint(r.Won),
+			fmt.Sprintf("%.0f%%", 100*r.WinRate()),
+			best,
+		})
+	}
+
+	tbl := table.New().
+		Border(lipgloss.NormalBorder()).
+		Headers("Board", "Played", "Won", "Win Rate", "Best").
+		Rows(rows...).
+		StyleFunc(func(row, col int) lipgloss.Style {
+			return sm.settings.Renderer.NewStyle().Padding(0, 1)
+		})
+	rendered.WriteString(tbl.Render())
+	rendered.WriteString("\n")
+
+	rendered.WriteString("\nEsc: Back\n")
+
+	return rendered.String()
+}
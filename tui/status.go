@@ -0,0 +1,174 @@
+package tui
+
+import (
+	"fmt"
+	"github.com/aymanbagabas/go-osc52/v2"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jboewer/minesshweeper/game"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxSeedLength fits every seed, which are 64 bit numbers.
+const maxSeedLength = 19
+
+// maxStatusSeconds is the most the timer shows, as on the classic game, so
+// that it keeps its width.
+const maxStatusSeconds = 999
+
+// statusTickMsg redraws the timer of g.
+type statusTickMsg struct {
+	game *game.Game
+}
+
+// tick keeps the timer running while the game is being played. Only one
+// tick is waited for at a time, and ticks stop once the game is over.
+func (gv *GameModel) tick() tea.Cmd {
+	if gv.ticking || gv.Game.State() != game.StatePlaying {
+		return nil
+	}
+	gv.ticking = true
+	return gv.timer()
+}
+
+func (gv GameModel) timer() tea.Cmd {
+	g := gv.Game
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return statusTickMsg{g}
+	})
+}
+
+// face sums up the state of the game like the smiley of the classic game.
+func face(s game.State) string {
+	switch s {
+	case game.StateWon:
+		return "B) Won"
+	case game.StateLost:
+		return "X( Lost"
+	default:
+		return ":) Playing"
+	}
+}
+
+// renderStatus writes the mines left, the time, how much of the board is
+// cleared, the cursor and the seed. Every field keeps its width while the
+// game goes on, and fields are left out from the end if the terminal is
+// too narrow for them.
+func (gv GameModel) renderStatus(rendered *strings.Builder) {
+	if gv.enteringSeed {
+		prompt := gv.styles.status.Render("Seed: " + gv.seed + "_")
+		rendered.WriteString(prompt + "  Enter: Play  Esc: Cancel" + gv.renderNotice())
+		return
+	}
+
+	g := gv.Game
+	stats := g.Stats()
+
+	mines := g.GetMineCount()
+	cleared := 0
+	if stats.SafeCells > 0 {
+		cleared = 100 * stats.Revealed / stats.SafeCells
+	}
+	seconds := min(int(stats.Elapsed.Seconds()), maxStatusSeconds)
+	x := len(strconv.Itoa(g.GetGridWidth()))
+	y := len(strconv.Itoa(g.GetGridHeight()))
+
+	fields := []string{
+		fmt.Sprintf("%-10s", face(g.State())),
+		fmt.Sprintf("Mines %*d", max(3, len(strconv.Itoa(mines))+1), mines-g.GetFlagCount()),
+		fmt.Sprintf("Time %03d", seconds),
+		fmt.Sprintf("%3d%% cleared", cleared),
+		fmt.Sprintf("At %*d,%-*d", x, gv.Cursor.x+1, y, gv.Cursor.y+1),
+	}
+	if !gv.hideSeed {
+		fields = append(fields, fmt.Sprintf("Seed %d", g.Seed()))
+	}
+
+	hint := ""
+	if gv.over() {
+		hint = "  Enter: Back to Menu"
+	}
+	hint += gv.renderNotice()
+
+	status := strings.Join(fields, "  ")
+	for len(fields) > 1 && gv.width > 0 && lipgloss.Width(status+hint) > gv.width {
+		fields = fields[:len(fields)-1]
+		status = strings.Join(fields, "  ")
+	}
+
+	rendered.WriteString(gv.styles.status.Render(status) + hint)
+}
+
+func (gv GameModel) renderNotice() string {
+	if gv.notice == "" {
+		return ""
+	}
+	return "  " + gv.notice
+}
+
+// updateSeed lets the player type in the seed of the board to play next.
+func (gv GameModel) updateSeed(msg tea.KeyMsg) GameModel {
+	switch msg.Type {
+	case tea.KeyEsc:
+		gv.enteringSeed = false
+	case tea.KeyEnter:
+		seed, err := strconv.ParseInt(gv.seed, 10, 64)
+		if err != nil {
+			gv.notice = "The seed must be a number"
+			return gv
+		}
+		gv.enteringSeed = false
+		gv.Reseed(seed)
+		gv.follow()
+	default:
+		gv.seed = editLine(gv.seed, msg, maxSeedLength, isDigitRune)
+	}
+	return gv
+}
+
+// copySeed copies the seed of the board to the player's clipboard with an
+// OSC 52 escape sequence, which terminals also understand over SSH.
+func (gv GameModel) copySeed() tea.Cmd {
+	seed := strconv.FormatInt(gv.Game.Seed(), 10)
+	out := gv.settings.Renderer.Output()
+	return func() tea.Msg {
+		if _, err := osc52.New(seed).WriteTo(out); err != nil {
+			log.Println("Could not copy the seed:", err)
+		}
+		return nil
+	}
+}
+
+// renderSummary draws the statistics of a won game in a box over the
+// board, until the player moves to look at it.
+func (gv GameModel) renderSummary(grid string) string {
+	if gv.hideSummary || gv.Game.State() != game.StateWon {
+		return grid
+	}
+
+	stats := gv.Game.Stats()
+	lines := []string{
+		gv.settings.Renderer.NewStyle().Bold(true).Render("You won!"),
+		"",
+		fmt.Sprintf("%-12s%.1fs", "Time", stats.Elapsed.Seconds()),
+		fmt.Sprintf("%-12s%d", "3BV", stats.ThreeBV),
+		fmt.Sprintf("%-12s%.2f", "3BV/s", stats.ThreeBVPerSecond()),
+		fmt.Sprintf("%-12s%d", "Clicks", stats.Clicks),
+		fmt.Sprintf("%-12s%.0f%%", "Efficiency", 100*stats.Efficiency()),
+		"",
+		"Move to see the board",
+	}
+	box := gv.styles.summary.Render(strings.Join(lines, "\n"))
+
+	// The grid ends with a newline, which isn't a line to draw on.
+	grid = strings.TrimSuffix(grid, "\n")
+	width, height := lipgloss.Size(grid)
+	boxWidth, boxHeight := lipgloss.Size(box)
+	if boxWidth > width || boxHeight > height {
+		return grid + "\n"
+	}
+	return overlay(grid, box, (width-boxWidth)/2, (height-boxHeight)/2) + "\n"
+}
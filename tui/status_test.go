@@ -0,0 +1,86 @@
+package tui_test
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jboewer/minesshweeper/game"
+	"github.com/jboewer/minesshweeper/tui"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestGameModel_Status(t *testing.T) {
+	g, err := game.NewWithDifficulty(game.Beginner, game.WithSeed(42))
+	assert.NoError(t, err)
+
+	m := press(tui.NewGameModel(g, tui.DefaultSettings(lipgloss.NewRenderer(io.Discard))), "d", "f")
+	view := m.View()
+
+	assert.Contains(t, view, ":) Playing")
+	assert.Contains(t, view, "Mines   9")
+	assert.Contains(t, view, "Time 000")
+	assert.Contains(t, view, "  0% cleared")
+	assert.Contains(t, view, "At 2,1")
+	assert.Contains(t, view, "Seed 42")
+}
+
+func TestGameModel_WinSummary(t *testing.T) {
+	grid := make(game.Grid, 8)
+	for y := range grid {
+		grid[y] = make([]int, 10)
+	}
+	grid[0][0] = 1
+	g, err := game.NewFromGrid(grid)
+	assert.NoError(t, err)
+	g.RevealCell(9, 7)
+
+	m := tui.NewGameModel(g, tui.DefaultSettings(lipgloss.NewRenderer(io.Discard)))
+	assert.Contains(t, m.View(), "You won!")
+	assert.Contains(t, m.View(), "Clicks      1")
+
+	view := press(m, "d").View()
+	assert.NotContains(t, view, "You won!")
+	assert.Contains(t, view, " F ")
+}
+
+func TestGameModel_WonByFlags(t *testing.T) {
+	g, err := game.NewFromGrid(game.Grid{
+		{1, 0, 0},
+		{0, 0, 0},
+		{0, 0, 1},
+	})
+	assert.NoError(t, err)
+	g.PlaceFlag(0, 0)
+	g.PlaceFlag(2, 2)
+
+	m := tui.NewGameModel(g, tui.DefaultSettings(lipgloss.NewRenderer(io.Discard)))
+	view := press(m, "d").View()
+
+	assert.Equal(t, 2, strings.Count(view, " F "))
+}
+
+func TestGameModel_Replay(t *testing.T) {
+	g, err := game.NewWithDifficulty(game.Beginner, game.WithSeed(42))
+	assert.NoError(t, err)
+	m := tui.NewGameModel(g, tui.DefaultSettings(lipgloss.NewRenderer(io.Discard)))
+
+	t.Run("Retry", func(t *testing.T) {
+		press(m, " ", "R")
+
+		assert.Equal(t, int64(42), g.Seed())
+		assert.Zero(t, g.Stats().Clicks)
+	})
+	t.Run("Enter a seed", func(t *testing.T) {
+		assert.Contains(t, press(m, "S", "enter").View(), "The seed must be a number")
+
+		view := press(m, "S", "7", "x", "enter").View()
+
+		assert.Equal(t, int64(7), g.Seed())
+		assert.Equal(t, 10, g.GetMineCount())
+		assert.Contains(t, view, "Seed 7")
+	})
+	t.Run("Copy the seed", func(t *testing.T) {
+		assert.Contains(t, press(m, "c").View(), "Seed copied to the clipboard")
+	})
+}
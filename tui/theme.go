@@ -0,0 +1,239 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"github.com/charmbracelet/lipgloss"
+	"gopkg.in/yaml.v3"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Colors are hex codes or ANSI colour numbers. Empty keeps the terminal's.
+type Colors struct {
+	Foreground string `yaml:"fg"`
+	Background string `yaml:"bg"`
+}
+
+// Theme decides the colours a board is drawn with.
+type Theme struct {
+	Name string `yaml:"name"`
+	// Numbers are the colours of the cells with 0 to 8 adjacent mines.
+	Numbers    [9]Colors `yaml:"numbers"`
+	Unrevealed Colors    `yaml:"unrevealed"`
+	Flag       Colors    `yaml:"flag"`
+	Mine       Colors    `yaml:"mine"`
+	Exploded   Colors    `yaml:"exploded"`
+	WrongFlag  Colors    `yaml:"wrong_flag"`
+	Cursor     Colors    `yaml:"cursor"`
+	Border     string    `yaml:"border"`
+	Status     string    `yaml:"status"`
+	// Symbols replace the letters F, M, B and X.
+	Symbols map[string]string `yaml:"symbols"`
+	// ShapeCues always marks the cursors with brackets.
+	ShapeCues bool `yaml:"shape_cues"`
+}
+
+var (
+	Classic = Theme{
+		Name: "classic",
+		Numbers: [9]Colors{
+			{Foreground: "#292929"},
+			{Foreground: "#74adf2"},
+			{Foreground: "#00FF00"},
+			{Foreground: "#FF0000"},
+			{Foreground: "#28706d"},
+			{Foreground: "#b06446"},
+			{Foreground: "#FF0000"},
+			{Foreground: "#8a7101"},
+			{Foreground: "#111", Background: "#bfbfbf"},
+		},
+		Unrevealed: Colors{Background: "#bfbfbf"},
+		Flag:       Colors{Foreground: "#111", Background: "#ffee00"},
+		Exploded:   Colors{Foreground: "#111", Background: "#FF0000"},
+		WrongFlag:  Colors{Foreground: "#FF0000", Background: "#ffee00"},
+		Cursor:     Colors{Foreground: "#111", Background: "#FF33FF"},
+	}
+
+	// Dark only uses the xterm 256 colour palette.
+	Dark = Theme{
+		Name: "dark",
+		Numbers: [9]Colors{
+			{Foreground: "#4e4e4e"},
+			{Foreground: "#5fafff"},
+			{Foreground: "#87d787"},
+			{Foreground: "#ff5f5f"},
+			{Foreground: "#af87ff"},
+			{Foreground: "#d7875f"},
+			{Foreground: "#5fd7d7"},
+			{Foreground: "#d7d7d7"},
+			{Foreground: "#878787"},
+		},
+		Unrevealed: Colors{Background: "#3a3a3a"},
+		Flag:       Colors{Foreground: "#1c1c1c", Background: "#d7af00"},
+		Mine:       Colors{Foreground: "#ff5f5f"},
+		Exploded:   Colors{Foreground: "#1c1c1c", Background: "#d70000"},
+		WrongFlag:  Colors{Foreground: "#d70000", Background: "#d7af00"},
+		Cursor:     Colors{Foreground: "#1c1c1c", Background: "#87afff"},
+		Border:     "#585858",
+		Status:     "#bcbcbc",
+	}
+
+	// HighContrast only uses the 16 basic colours at full brightness.
+	HighContrast = Theme{
+		Name: "high-contrast",
+		Numbers: [9]Colors{
+			{Foreground: "8"},
+			{Foreground: "12"},
+			{Foreground: "10"},
+			{Foreground: "9"},
+			{Foreground: "13"},
+			{Foreground: "11"},
+			{Foreground: "14"},
+			{Foreground: "15"},
+			{Foreground: "0", Background: "15"},
+		},
+		Unrevealed: Colors{Background: "7"},
+		Flag:       Colors{Foreground: "0", Background: "11"},
+		Mine:       Colors{Foreground: "15", Background: "0"},
+		Exploded:   Colors{Foreground: "15", Background: "9"},
+		WrongFlag:  Colors{Foreground: "9", Background: "11"},
+		Cursor:     Colors{Foreground: "0", Background: "13"},
+		Border:     "15",
+		Status:     "15",
+	}
+
+	// Colorblind uses the Okabe-Ito palette and shape cues.
+	Colorblind = Theme{
+		Name: "colorblind",
+		Numbers: [9]Colors{
+			{Foreground: "#4d4d4d"},
+			{Foreground: "#56B4E9"},
+			{Foreground: "#009E73"},
+			{Foreground: "#D55E00"},
+			{Foreground: "#0072B2"},
+			{Foreground: "#E69F00"},
+			{Foreground: "#CC79A7"},
+			{Foreground: "#F0E442"},
+			{Foreground: "#000", Background: "#999999"},
+		},
+		Unrevealed: Colors{Background: "#bfbfbf"},
+		Flag:       Colors{Foreground: "#000", Background: "#F0E442"},
+		Exploded:   Colors{Foreground: "#000", Background: "#D55E00"},
+		WrongFlag:  Colors{Foreground: "#D55E00", Background: "#F0E442"},
+		Cursor:     Colors{Foreground: "#000", Background: "#56B4E9"},
+		Symbols:    map[string]string{"M": "*", "B": "X", "X": "x"},
+		ShapeCues:  true,
+	}
+
+	// Themes are the themes that come with the game.
+	Themes = []Theme{Classic, Dark, HighContrast, Colorblind}
+)
+
+// CellColors returns the colours of a cell shown as CellText.
+func (t Theme) CellColors(cell string) Colors {
+	switch cell {
+	case " ":
+		return t.Unrevealed
+	case "F":
+		return t.Flag
+	case "M":
+		return t.Mine
+	case "B":
+		return t.Exploded
+	case "X":
+		return t.WrongFlag
+	}
+
+	if n, err := strconv.Atoi(cell); err == nil && n >= 0 && n < len(t.Numbers) {
+		return t.Numbers[n]
+	}
+	return Colors{}
+}
+
+// Symbol returns what a cell shown as CellText is drawn as.
+func (t Theme) Symbol(cell string) string {
+	if s, ok := t.Symbols[cell]; ok {
+		return s
+	}
+	return cell
+}
+
+var colorPattern = regexp.MustCompile(`^(#[0-9a-fA-F]{3}|#[0-9a-fA-F]{6}|[0-9]{1,3})?$`)
+
+// Validate checks that the theme's colours and symbols can be drawn.
+func (t Theme) Validate() error {
+	if t.Name == "" {
+		return errors.New("theme has no name")
+	}
+
+	colors := []string{t.Border, t.Status}
+	for _, c := range append(t.Numbers[:], t.Unrevealed, t.Flag, t.Mine, t.Exploded, t.WrongFlag, t.Cursor) {
+		colors = append(colors, c.Foreground, c.Background)
+	}
+	for _, c := range colors {
+		if !colorPattern.MatchString(c) {
+			return fmt.Errorf("theme %s: invalid colour %q", t.Name, c)
+		}
+		if n, err := strconv.Atoi(c); err == nil && n > 255 {
+			return fmt.Errorf("theme %s: invalid colour %q", t.Name, c)
+		}
+	}
+
+	for cell, s := range t.Symbols {
+		if !strings.Contains("FMBX", cell) || len(cell) != 1 {
+			return fmt.Errorf("theme %s: only F, M, B and X have symbols", t.Name)
+		}
+		if lipgloss.Width(s) != 1 {
+			return fmt.Errorf("theme %s: symbol %q is not one column wide", t.Name, s)
+		}
+	}
+
+	return nil
+}
+
+// LoadThemes reads custom themes from a YAML file. Missing colours are
+// taken from the classic theme.
+func LoadThemes(path string) ([]Theme, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var nodes []yaml.Node
+	if err := yaml.Unmarshal(data, &nodes); err != nil {
+		return nil, err
+	}
+
+	themes := make([]Theme, 0, len(nodes))
+	for _, n := range nodes {
+		t := Classic
+		t.Name = ""
+		t.Symbols = nil
+		if err := n.Decode(&t); err != nil {
+			return nil, err
+		}
+		if err := t.Validate(); err != nil {
+			return nil, err
+		}
+		_, builtIn := FindTheme(Themes, t.Name)
+		if _, ok := FindTheme(themes, t.Name); ok || builtIn {
+			return nil, fmt.Errorf("theme %s already exists", t.Name)
+		}
+		themes = append(themes, t)
+	}
+
+	return themes, nil
+}
+
+// FindTheme looks up a theme by its name.
+func FindTheme(themes []Theme, name string) (Theme, bool) {
+	for _, t := range themes {
+		if strings.EqualFold(t.Name, name) {
+			return t, true
+		}
+	}
+	return Theme{}, false
+}
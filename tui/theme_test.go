@@ -0,0 +1,75 @@
+package tui_test
+
+import (
+	"github.com/jboewer/minesshweeper/tui"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBuiltInThemes(t *testing.T) {
+	for _, theme := range tui.Themes {
+		assert.NoError(t, theme.Validate(), theme.Name)
+	}
+}
+
+func TestTheme_CellColors(t *testing.T) {
+	assert.Equal(t, tui.Colors{Foreground: "#74adf2"}, tui.Classic.CellColors("1"))
+	assert.Equal(t, tui.Classic.Unrevealed, tui.Classic.CellColors(" "))
+	assert.Equal(t, tui.Classic.Exploded, tui.Classic.CellColors("B"))
+	assert.Equal(t, tui.Classic.WrongFlag, tui.Classic.CellColors("X"))
+	assert.Equal(t, tui.Colors{}, tui.Classic.CellColors("?"))
+}
+
+func TestTheme_Symbol(t *testing.T) {
+	assert.Equal(t, "F", tui.Colorblind.Symbol("F"))
+	assert.Equal(t, "X", tui.Colorblind.Symbol("B"))
+}
+
+func TestLoadThemes(t *testing.T) {
+	write := func(t *testing.T, content string) string {
+		path := filepath.Join(t.TempDir(), "themes.yaml")
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+		return path
+	}
+
+	t.Run("Partial themes start from classic", func(t *testing.T) {
+		themes, err := tui.LoadThemes(write(t, `
+- name: solarized
+  unrevealed: {bg: "#073642"}
+  cursor: {fg: "#002b36", bg: "#b58900"}
+  symbols: {F: "!"}
+`))
+
+		assert.NoError(t, err)
+		assert.Len(t, themes, 1)
+		assert.Equal(t, "solarized", themes[0].Name)
+		assert.Equal(t, tui.Colors{Background: "#073642"}, themes[0].Unrevealed)
+		assert.Equal(t, tui.Classic.Numbers, themes[0].Numbers)
+		assert.Equal(t, "!", themes[0].Symbol("F"))
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		for _, content := range []string{
+			"- unrevealed: {bg: \"#000\"}\n",
+			"- name: x\n  flag: {fg: red}\n",
+			"- name: x\n  flag: {fg: \"300\"}\n",
+			"- name: x\n  symbols: {F: \"!!\"}\n",
+			"- name: x\n  symbols: {\"1\": \"!\"}\n",
+			"- name: Dark\n",
+			"- name: x\n- name: x\n",
+			"name: x\n",
+		} {
+			_, err := tui.LoadThemes(write(t, content))
+			assert.Error(t, err, content)
+		}
+	})
+}
+
+func TestServices_Theme(t *testing.T) {
+	services := &tui.Services{}
+
+	assert.Equal(t, "dark", services.Theme("Dark").Name)
+	assert.Equal(t, "classic", services.Theme("").Name)
+	assert.Equal(t, "classic", services.Theme("missing").Name)
+}
@@ -1,24 +1,50 @@
 package tui
 
 import (
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
-	"github.com/charmbracelet/lipgloss/table"
 	"github.com/jboewer/minesshweeper/game"
+	"github.com/jboewer/minesshweeper/room"
 	"log"
 	"strconv"
 	"strings"
 )
 
-func NewGameModel(g *game.Game) GameModel {
+// NewGameModel returns a model for a game played alone.
+func NewGameModel(g *game.Game, settings Settings) GameModel {
+	return newGameModel(g, soloMoves{g}, settings)
+}
+
+func newGameModel(g *game.Game, moves moves, settings Settings) GameModel {
 	return GameModel{
 		Game: g,
 		Cursor: Cursor{
 			game: g,
 		},
+		moves:    moves,
+		settings: settings,
+		styles:   newCellStyles(settings),
+		help:     newHelp(settings),
+		bottom:   1,
+		autoMode: true,
+		ticking:  true,
 	}
 }
 
+// NewRoomGameModel returns a model for a board shared with other players.
+// Moves are made through the room so that they reach everyone in it.
+func NewRoomGameModel(r *room.Room, p room.Player, updates <-chan struct{}, settings Settings) GameModel {
+	m := newGameModel(r.Game, roomMoves{r, p.ID}, settings)
+	m.room = r
+	m.player = p
+	m.updates = updates
+	m.bottom++
+
+	return m
+}
+
 type Cursor struct {
 	x    int
 	y    int
@@ -56,116 +82,325 @@ func (c *Cursor) Right() {
 type GameModel struct {
 	Game   *game.Game
 	Cursor Cursor
+
+	moves    moves
+	settings Settings
+	styles   *cellStyles
+	help     help.Model
+	// hideHelp leaves out the keys, for boards that are only watched.
+	hideHelp bool
+	// hideSeed keeps the seed from the player on race boards, where it
+	// would give the layout away.
+	hideSeed bool
+	room     *room.Room
+	player   room.Player
+	updates  <-chan struct{}
+	// back is the screen the game was started from. Without one, leaving
+	// the game quits.
+	back tea.Model
+	// left is called when the player leaves the game, to take them out of
+	// the room they joined and stop showing the game to spectators.
+	left func()
+
+	// top and bottom are the lines above and below the grid, besides the
+	// help.
+	top    int
+	bottom int
+	// width and height are the terminal size, or zero until it is known.
+	width   int
+	height  int
+	scrollX int
+	scrollY int
+	// mode is how densely the board is drawn, unless autoMode is set.
+	mode     RenderMode
+	autoMode bool
+	// leftDown is set while the left mouse button is held, and chorded once
+	// the right button was pressed with it.
+	leftDown bool
+	chorded  bool
+	// ticking is set while a tick of the timer is on its way. The first
+	// one is sent by Init.
+	ticking bool
+	// hideSummary is set once the player moves on a won board, to look at
+	// it without the statistics in the way.
+	hideSummary bool
+	// seed is what the player typed in so far while enteringSeed is set.
+	enteringSeed bool
+	seed         string
+	// notice tells the player how their last action went, until the next
+	// key is pressed.
+	notice string
+}
+
+type roomUpdateMsg struct{}
+
+func waitForRoomUpdate(updates <-chan struct{}) tea.Cmd {
+	return func() tea.Msg {
+		if _, ok := <-updates; !ok {
+			return nil
+		}
+		return roomUpdateMsg{}
+	}
 }
 
 func (gv GameModel) View() string {
 	rendered := &strings.Builder{}
 
-	gv.renderGameGrid(rendered)
-
-	// Write game state
-	switch gv.Game.State() {
-	case game.StatePlaying:
-		rendered.WriteString("Playing")
-	case game.StateWon:
-		rendered.WriteString("Won")
-	case game.StateLost:
-		rendered.WriteString("Lost")
-	}
+	grid := &strings.Builder{}
+	gv.renderGameGrid(grid)
+	rendered.WriteString(gv.renderSummary(grid.String()))
 
+	gv.renderStatus(rendered)
 	rendered.WriteString("\n")
 
+	if gv.room != nil {
+		gv.renderRoom(rendered)
+	}
+
 	gv.renderInstructions(rendered)
 
 	// Send the UI for rendering
 	return rendered.String()
 }
 
-func (gv GameModel) renderGameGrid(rendered *strings.Builder) {
-	grid := gv.Game.GetGrid()
-
-	rows := make([][]string, grid.GetHeight())
-	for y := 0; y < grid.GetHeight(); y++ {
-		rows[y] = make([]string, grid.GetWidth())
-
-		for x := 0; x < grid.GetWidth(); x++ {
-			c := grid.Get(x, y)
-
-			switch c {
-			case game.CellUnrevealed:
-				rows[y][x] = " "
-			case game.CellFlag:
-				rows[y][x] = "F"
-			case game.CellMine:
-				rows[y][x] = "M"
-			default:
-				rows[y][x] = strconv.Itoa(c)
-			}
-
+// CellText is how a cell is shown on the board: F for flags, M for mines,
+// B for the mine that went off and X for flags that turned out to be wrong.
+func CellText(c game.CellView) string {
+	switch c.State {
+	case game.CellStateFlagged:
+		if c.WrongFlag {
+			return "X"
+		}
+		return "F"
+	case game.CellStateMine:
+		if c.Exploded {
+			return "B"
 		}
+		return "M"
+	case game.CellStateRevealed:
+		return strconv.Itoa(c.AdjacentMines)
+	default:
+		return " "
 	}
+}
 
-	tbl := table.New().
-		Border(lipgloss.NormalBorder()).
-		BorderRow(true).
-		BorderColumn(true).
-		Rows(rows...).
-		StyleFunc(func(row, col int) lipgloss.Style {
-			fg, bg := getCellColors(rows[row-1][col])
-
-			if row == gv.Cursor.y+1 && col == gv.Cursor.x {
-				fg, bg = getCursorColors(fg, bg)
-			}
-
-			return lipgloss.NewStyle().
-				Foreground(fg).
-				Background(bg).
-				Padding(0, 1)
-		})
-
-	rendered.WriteString(tbl.Render())
+func (gv GameModel) renderRoom(rendered *strings.Builder) {
+	rendered.WriteString("Room " + gv.room.Code + ":")
+	for _, p := range gv.room.Players() {
+		name := gv.settings.Renderer.NewStyle().Foreground(lipgloss.Color(p.Color)).Render(p.Name)
+		rendered.WriteString(" " + name)
+	}
 	rendered.WriteString("\n")
 }
 
 func (gv GameModel) Init() tea.Cmd {
-	return nil
+	if gv.room != nil {
+		return tea.Batch(waitForRoomUpdate(gv.updates), gv.timer())
+	}
+	return gv.timer()
 }
 
 func (gv GameModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	m, cmd := gv.update(msg)
+	board, ok := m.(GameModel)
+	if !ok {
+		return m, cmd
+	}
+
+	// Resetting the game, here or in a room, starts the timer again and
+	// brings back the summary for the next win.
+	tick := board.tick()
+	if board.Game.State() == game.StatePlaying {
+		board.hideSummary = false
+	}
+	return board, tea.Batch(cmd, tick)
+}
+
+func (gv GameModel) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case statusTickMsg:
+		if msg.game != gv.Game {
+			return gv, nil
+		}
+		if gv.Game.State() != game.StatePlaying {
+			gv.ticking = false
+			return gv, nil
+		}
+		return gv, gv.timer()
+	case roomUpdateMsg:
+		return gv, waitForRoomUpdate(gv.updates)
+	case tea.WindowSizeMsg:
+		gv.width, gv.height = msg.Width, msg.Height
+		gv.help.Width = msg.Width
+		gv.follow()
+	case tea.MouseMsg:
+		return gv.updateMouse(msg), nil
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "ctrl+c", "q":
+		gv.notice = ""
+		if msg.Type != tea.KeyCtrlC && gv.enteringSeed {
+			return gv.updateSeed(msg), nil
+		}
+
+		keys := gv.keys()
+		switch {
+		case msg.Type == tea.KeyCtrlC:
 			return gv, tea.Quit
-		case "w", "k":
+		case key.Matches(msg, keys.Quit), gv.over() && msg.Type == tea.KeyEnter:
+			return gv.leave()
+		case key.Matches(msg, keys.Up):
 			gv.Cursor.Up()
-		case "a", "h":
+			gv.moves.MoveCursor(gv.Cursor.x, gv.Cursor.y)
+		case key.Matches(msg, keys.Left):
 			gv.Cursor.Left()
-		case "s", "j":
+			gv.moves.MoveCursor(gv.Cursor.x, gv.Cursor.y)
+		case key.Matches(msg, keys.Down):
 			gv.Cursor.Down()
-		case "d", "l":
+			gv.moves.MoveCursor(gv.Cursor.x, gv.Cursor.y)
+		case key.Matches(msg, keys.Right):
 			gv.Cursor.Right()
-		case "f":
-			gv.Game.ToggleFlag(gv.Cursor.x, gv.Cursor.y)
-		case " ":
-			gv.Game.RevealCell(gv.Cursor.x, gv.Cursor.y)
-		case "r":
+			gv.moves.MoveCursor(gv.Cursor.x, gv.Cursor.y)
+		case key.Matches(msg, keys.Flag):
+			gv.moves.ToggleFlag(gv.Cursor.x, gv.Cursor.y)
+		case key.Matches(msg, keys.Reveal):
+			gv.moves.Reveal(gv.Cursor.x, gv.Cursor.y)
+		case key.Matches(msg, keys.Reset):
 			gv.Reset()
+		case key.Matches(msg, keys.Retry):
+			gv.Retry()
+		case key.Matches(msg, keys.Seed):
+			gv.enteringSeed, gv.seed = true, ""
+		case key.Matches(msg, keys.Copy):
+			gv.notice = "Seed copied to the clipboard"
+			return gv, gv.copySeed()
+		case key.Matches(msg, keys.Help):
+			gv.help.ShowAll = !gv.help.ShowAll
+		case key.Matches(msg, keys.View):
+			gv.nextMode()
+		}
+		if key.Matches(msg, keys.Up, keys.Left, keys.Down, keys.Right) && gv.Game.State() != game.StatePlaying {
+			gv.hideSummary = true
 		}
+		gv.follow()
 	}
 
 	return gv, nil
 }
 
+// over reports whether the game has ended and can be left with Enter.
+func (gv GameModel) over() bool {
+	return gv.back != nil && gv.Game.State() != game.StatePlaying
+}
+
+// leave returns to the screen the game was started from, with the window
+// size the game last saw.
+func (gv GameModel) leave() (tea.Model, tea.Cmd) {
+	if gv.left != nil {
+		gv.left()
+	}
+	if gv.back == nil {
+		return gv, tea.Quit
+	}
+	return startSized(gv.back, tea.WindowSizeMsg{Width: gv.width, Height: gv.height})
+}
+
+// onLeave adds f to what is done when the player leaves the game.
+func (gv *GameModel) onLeave(f func()) {
+	left := gv.left
+	gv.left = func() {
+		if left != nil {
+			left()
+		}
+		f()
+	}
+}
+
+// updateMouse moves the cursor to the cell under the mouse and plays it.
+// Cells are revealed when the left button is released, so that pressing the
+// right button as well can turn it into a chord.
+func (gv GameModel) updateMouse(msg tea.MouseMsg) GameModel {
+	c, ok := gv.layout().CellAt(msg.X, msg.Y)
+	if !ok {
+		if msg.Action == tea.MouseActionRelease {
+			gv.leftDown, gv.chorded = false, false
+		}
+		return gv
+	}
+
+	if c.X != gv.Cursor.x || c.Y != gv.Cursor.y {
+		gv.Cursor.x, gv.Cursor.y = c.X, c.Y
+		gv.moves.MoveCursor(c.X, c.Y)
+	}
+
+	switch msg.Action {
+	case tea.MouseActionPress:
+		switch msg.Button {
+		case tea.MouseButtonLeft:
+			gv.leftDown, gv.chorded = true, false
+		case tea.MouseButtonRight:
+			if gv.leftDown {
+				gv.chorded = true
+				gv.moves.Chord(c.X, c.Y)
+			} else {
+				gv.moves.ToggleFlag(c.X, c.Y)
+			}
+		case tea.MouseButtonMiddle:
+			gv.moves.Chord(c.X, c.Y)
+		}
+	case tea.MouseActionRelease:
+		if gv.leftDown && !gv.chorded {
+			gv.moves.Reveal(c.X, c.Y)
+		}
+		gv.leftDown, gv.chorded = false, false
+	}
+
+	return gv
+}
+
+// renderInstructions shows the player's keys, with all of them once the
+// player asked for help.
 func (gv GameModel) renderInstructions(rendered *strings.Builder) {
-	rendered.WriteString("WASD/HJKL: Move Around\n")
-	rendered.WriteString("F: Toggle Flag\n")
-	rendered.WriteString("Space: Reveal\n")
-	rendered.WriteString("R: Reset\n")
-	rendered.WriteString("Q: Quit\n")
+	if gv.hideHelp {
+		return
+	}
+	rendered.WriteString(gv.help.View(gv.keys()) + "\n")
+}
+
+// helpHeight is the number of lines renderInstructions takes.
+func (gv GameModel) helpHeight() int {
+	if gv.hideHelp {
+		return 0
+	}
+	return lipgloss.Height(gv.help.View(gv.keys()))
+}
+
+// keys are the player's keys as the help shows them. The quit key leaves
+// to the menu when there is one, and the seed can't be copied or entered
+// when it is hidden.
+func (gv GameModel) keys() KeyMap {
+	keys := gv.settings.Keys
+	if gv.back != nil {
+		keys.Quit.SetHelp(keys.Quit.Help().Key, "menu")
+	}
+	if gv.hideSeed {
+		keys.Seed.SetEnabled(false)
+		keys.Copy.SetEnabled(false)
+	}
+	return keys
 }
 
 func (gv GameModel) Reset() {
 	log.Println("Resetting game")
-	gv.Game.Reset()
+	gv.moves.Reset()
+}
+
+// Retry starts over on the same board.
+func (gv GameModel) Retry() {
+	log.Println("Restarting game")
+	gv.moves.Restart()
+}
+
+// Reseed starts over on the board laid out from seed.
+func (gv GameModel) Reseed(seed int64) {
+	log.Println("Reseeding game with", seed)
+	gv.moves.Reseed(seed)
 }
@@ -0,0 +1,260 @@
+package tui
+
+import (
+	"fmt"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+	"github.com/jboewer/minesshweeper/game"
+	"github.com/jboewer/minesshweeper/versus"
+	"strings"
+)
+
+const progressBarWidth = 20
+
+// raceHeaderHeight is the number of lines above the board during a race.
+const raceHeaderHeight = 2
+
+func NewVersusModel(m *versus.Match, playerID string, updates <-chan struct{}, settings Settings) VersusModel {
+	vm := VersusModel{
+		match:    m,
+		playerID: playerID,
+		updates:  updates,
+		settings: settings,
+	}
+	vm.ensureBoard()
+
+	return vm
+}
+
+// VersusModel shows the player's own board in a race, together with the
+// progress of everyone else in the match.
+type VersusModel struct {
+	match    *versus.Match
+	playerID string
+	updates  <-chan struct{}
+	settings Settings
+	board    *GameModel
+	// size is the last window size, handed to the board once it exists.
+	size tea.WindowSizeMsg
+	// publish, if set, makes the player's board visible to spectators.
+	publish func(GameModel) GameModel
+	// back is the lobby the race was started from, and leave takes the
+	// player out of the match on the way back. Without a lobby, leaving
+	// quits.
+	back  tea.Model
+	leave func()
+	err   error
+}
+
+type matchUpdateMsg struct{}
+
+func waitForMatchUpdate(updates <-chan struct{}) tea.Cmd {
+	return func() tea.Msg {
+		if _, ok := <-updates; !ok {
+			return nil
+		}
+		return matchUpdateMsg{}
+	}
+}
+
+func (vm VersusModel) Init() tea.Cmd {
+	return waitForMatchUpdate(vm.updates)
+}
+
+func (vm VersusModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case matchUpdateMsg:
+		vm.ensureBoard()
+		return vm, waitForMatchUpdate(vm.updates)
+	case tea.WindowSizeMsg:
+		vm.size = msg
+		if vm.board != nil {
+			m, _ := vm.board.Update(msg)
+			board := m.(GameModel)
+			vm.board = &board
+		}
+	case tea.MouseMsg:
+		if vm.match.State() != versus.StateRacing || vm.board == nil {
+			return vm, nil
+		}
+		m, cmd := vm.board.Update(msg)
+		board := m.(GameModel)
+		vm.board = &board
+		return vm, cmd
+	case tea.KeyMsg:
+		if msg.Type == tea.KeyCtrlC {
+			return vm, tea.Quit
+		}
+		if key.Matches(msg, vm.settings.Keys.Quit) {
+			return vm.exit()
+		}
+
+		switch vm.match.State() {
+		case versus.StateWaiting:
+			if msg.String() == "enter" {
+				vm.err = vm.match.Begin()
+				vm.ensureBoard()
+			}
+		case versus.StateRacing:
+			if vm.board == nil {
+				return vm, nil
+			}
+			m, cmd := vm.board.Update(msg)
+			board := m.(GameModel)
+			vm.board = &board
+			return vm, cmd
+		}
+	}
+
+	return vm, nil
+}
+
+// exit returns to the lobby, leaving the match.
+func (vm VersusModel) exit() (tea.Model, tea.Cmd) {
+	if vm.back == nil {
+		return vm, tea.Quit
+	}
+	if vm.leave != nil {
+		vm.leave()
+	}
+	return startSized(vm.back, vm.size)
+}
+
+// ensureBoard picks up the player's board once the race has started.
+func (vm *VersusModel) ensureBoard() {
+	if vm.board != nil {
+		return
+	}
+
+	g, err := vm.match.Game(vm.playerID)
+	if err != nil {
+		return
+	}
+
+	board := newGameModel(g, raceMoves{vm.match, vm.playerID}, vm.settings)
+	board.Cursor.x = vm.match.Start.X
+	board.Cursor.y = vm.match.Start.Y
+	// The seed and the start cell lay out the whole board.
+	board.hideSeed = true
+	// The progress of every racer and the instructions follow the board.
+	board.top = raceHeaderHeight
+	board.bottom = len(vm.match.Racers()) + 1
+	board.width, board.height = vm.size.Width, vm.size.Height
+	board.follow()
+	if vm.publish != nil {
+		board = vm.publish(board)
+	}
+	vm.board = &board
+}
+
+func (vm VersusModel) View() string {
+	rendered := &strings.Builder{}
+
+	rendered.WriteString("Race " + vm.match.Code + "\n\n")
+
+	switch vm.match.State() {
+	case versus.StateWaiting:
+		rendered.WriteString("Waiting for players:\n")
+		for _, r := range vm.match.Racers() {
+			rendered.WriteString("  " + r.Name + "\n")
+		}
+		rendered.WriteString("\nEnter: Start Race\n")
+		rendered.WriteString(vm.quitHint() + "\n")
+	case versus.StateRacing:
+		if vm.board != nil {
+			vm.board.renderGameGrid(rendered)
+			vm.renderProgress(rendered)
+			rendered.WriteString("\n")
+			vm.board.renderInstructions(rendered)
+		}
+	case versus.StateFinished:
+		if vm.board != nil {
+			vm.board.renderGameGrid(rendered)
+		}
+		vm.renderResults(rendered)
+		rendered.WriteString("\n" + vm.quitHint() + "\n")
+	}
+
+	if vm.err != nil {
+		rendered.WriteString("\nError: " + vm.err.Error() + "\n")
+	}
+
+	return rendered.String()
+}
+
+func (vm VersusModel) quitHint() string {
+	if vm.back == nil {
+		return keyHint(vm.settings.Keys.Quit, "Quit")
+	}
+	return keyHint(vm.settings.Keys.Quit, "Back to Lobby")
+}
+
+func (vm VersusModel) renderProgress(rendered *strings.Builder) {
+	for _, r := range vm.match.Racers() {
+		progress := 0.0
+		if r.Stats.SafeCells > 0 {
+			progress = float64(r.Stats.Revealed) / float64(r.Stats.SafeCells)
+		}
+		filled := int(progress * progressBarWidth)
+
+		fmt.Fprintf(
+			rendered,
+			"%-12s %s%s %3.0f%% %s\n",
+			r.Name,
+			strings.Repeat("█", filled),
+			strings.Repeat("░", progressBarWidth-filled),
+			progress*100,
+			racerStatus(r, false),
+		)
+	}
+}
+
+func (vm VersusModel) renderResults(rendered *strings.Builder) {
+	rows := [][]string{}
+	for _, r := range vm.match.Racers() {
+		// The 3BV is that of the whole board, so the speed only means
+		// something for racers who cleared it.
+		speed := "-"
+		if r.State == game.StateWon {
+			speed = fmt.Sprintf("%.2f", r.Stats.ThreeBVPerSecond())
+		}
+		rows = append(rows, []string{
+			r.Name,
+			racerStatus(r, true),
+			fmt.Sprintf("%.1fs", r.Stats.Elapsed.Seconds()),
+			fmt.Sprint(r.Stats.Clicks),
+			fmt.Sprint(r.Stats.ThreeBV),
+			speed,
+		})
+	}
+
+	tbl := table.New().
+		Border(lipgloss.NormalBorder()).
+		Headers("Player", "Result", "Time", "Clicks", "3BV", "3BV/s").
+		Rows(rows...).
+		StyleFunc(func(row, col int) lipgloss.Style {
+			return vm.settings.Renderer.NewStyle().Padding(0, 1)
+		})
+
+	rendered.WriteString(tbl.Render())
+	rendered.WriteString("\n")
+}
+
+func racerStatus(r versus.Racer, finished bool) string {
+	switch {
+	case r.Winner:
+		return "Winner"
+	case r.Left:
+		return "Left"
+	case r.State == game.StateLost:
+		return "Forfeit"
+	case r.State == game.StateWon:
+		return "Cleared"
+	case finished:
+		return "Unfinished"
+	default:
+		return "Racing"
+	}
+}
@@ -0,0 +1,45 @@
+package tui_test
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jboewer/minesshweeper/tui"
+	"github.com/jboewer/minesshweeper/versus"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"testing"
+)
+
+func newRace(t *testing.T) (*versus.Registry, tui.VersusModel) {
+	t.Helper()
+
+	reg := versus.NewRegistry()
+	m, updates, err := reg.Create(versus.Settings{Width: 9, Height: 9, Mines: 10}, "a", "alice")
+	assert.NoError(t, err)
+	_, _, err = reg.Join(m.Code, "b", "bob")
+	assert.NoError(t, err)
+
+	return reg, tui.NewVersusModel(m, "a", updates, tui.DefaultSettings(lipgloss.NewRenderer(io.Discard)))
+}
+
+func TestVersusModel_HidesTheSeed(t *testing.T) {
+	_, vm := newRace(t)
+	racing := press(vm, "enter", "?")
+
+	_, cmd := racing.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+
+	assert.Nil(t, cmd)
+	assert.Contains(t, racing.View(), "less")
+	assert.NotContains(t, racing.View(), "seed")
+}
+
+func TestVersusModel_Results(t *testing.T) {
+	reg, vm := newRace(t)
+	racing := press(vm, "enter")
+
+	reg.Leave("b")
+	reg.Leave("a")
+
+	// Neither racer cleared the board, so neither has a speed.
+	assert.Contains(t, racing.View(), "│ -     │")
+}
@@ -0,0 +1,388 @@
+package versus
+
+import (
+	"errors"
+	"github.com/jboewer/minesshweeper/game"
+	"github.com/jboewer/minesshweeper/room"
+	"math/rand"
+	"strings"
+	"sync"
+)
+
+var (
+	ErrMatchNotFound = errors.New("match not found")
+	ErrNotInMatch    = errors.New("player is not in this match")
+	ErrMatchStarted  = errors.New("match has already started")
+	ErrNotStarted    = errors.New("match has not started yet")
+	ErrMatchOver     = errors.New("match is over")
+)
+
+type Settings struct {
+	Width  int
+	Height int
+	Mines  int
+}
+
+type State int
+
+const (
+	StateWaiting State = iota
+	StateRacing
+	StateFinished
+)
+
+// Racer is a player's standing in a match.
+type Racer struct {
+	ID     string
+	Name   string
+	State  game.State
+	Stats  game.Stats
+	Winner bool
+	// Left is set for players that disconnected during the race.
+	Left bool
+}
+
+type racer struct {
+	id      string
+	name    string
+	game    *game.Game
+	updates chan struct{}
+	left    bool
+}
+
+// Match is a race between players on identical boards. Every racer gets
+// their own game built from the same seed, with the same first cell
+// revealed for them. The first to clear their board wins; hitting a mine
+// forfeits.
+type Match struct {
+	Code     string
+	Settings Settings
+	Seed     int64
+	Start    game.Coordinate
+
+	mu     sync.Mutex
+	state  State
+	racers []*racer
+	winner string
+}
+
+func (m *Match) State() State {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.state
+}
+
+// Begin starts the race for everyone who has joined so far.
+func (m *Match) Begin() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.state != StateWaiting {
+		return ErrMatchStarted
+	}
+
+	d := game.Difficulty{
+		Width:  m.Settings.Width,
+		Height: m.Settings.Height,
+		Mines:  m.Settings.Mines,
+	}
+	for _, r := range m.racers {
+		g, err := game.NewWithOpening(d, m.Seed, m.Start)
+		if err != nil {
+			return err
+		}
+		r.game = g
+	}
+
+	m.state = StateRacing
+	m.broadcast("")
+
+	return nil
+}
+
+// Game returns the player's own board once the race has started.
+func (m *Match) Game(playerID string) (*game.Game, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r := m.racer(playerID)
+	if r == nil {
+		return nil, ErrNotInMatch
+	}
+	if r.game == nil {
+		return nil, ErrNotStarted
+	}
+
+	return r.game, nil
+}
+
+func (m *Match) activeGame(playerID string) (*game.Game, error) {
+	g, err := m.Game(playerID)
+	if err != nil {
+		return nil, err
+	}
+	if m.State() == StateFinished {
+		return nil, ErrMatchOver
+	}
+
+	return g, nil
+}
+
+func (m *Match) Reveal(playerID string, x, y int) (game.RevealResult, error) {
+	g, err := m.activeGame(playerID)
+	if err != nil {
+		return game.RevealResult{}, err
+	}
+
+	res, err := g.Reveal(x, y)
+	if err != nil {
+		return res, err
+	}
+
+	m.moved(playerID)
+	return res, nil
+}
+
+func (m *Match) Chord(playerID string, x, y int) (game.RevealResult, error) {
+	g, err := m.activeGame(playerID)
+	if err != nil {
+		return game.RevealResult{}, err
+	}
+
+	res, err := g.Chord(x, y)
+	if err != nil {
+		return res, err
+	}
+
+	m.moved(playerID)
+	return res, nil
+}
+
+func (m *Match) ToggleFlag(playerID string, x, y int) error {
+	g, err := m.activeGame(playerID)
+	if err != nil {
+		return err
+	}
+
+	g.ToggleFlag(x, y)
+	m.moved(playerID)
+
+	return nil
+}
+
+// moved checks whether the player's last move decided the race and tells
+// the other racers about their progress.
+func (m *Match) moved(playerID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.state != StateRacing {
+		return
+	}
+
+	if m.racer(playerID).game.State() == game.StateWon {
+		m.winner = playerID
+		m.state = StateFinished
+		m.broadcast("")
+		return
+	}
+
+	if m.allOut() {
+		m.state = StateFinished
+		m.broadcast("")
+		return
+	}
+
+	m.broadcast(playerID)
+}
+
+// allOut reports whether no racer is able to win anymore.
+func (m *Match) allOut() bool {
+	for _, r := range m.racers {
+		if !r.left && r.game.State() == game.StatePlaying {
+			return false
+		}
+	}
+	return true
+}
+
+func (m *Match) Racers() []Racer {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	racers := make([]Racer, 0, len(m.racers))
+	for _, r := range m.racers {
+		racer := Racer{
+			ID:     r.id,
+			Name:   r.name,
+			Winner: r.id == m.winner,
+			Left:   r.left,
+		}
+		if r.game != nil {
+			racer.State = r.game.State()
+			racer.Stats = r.game.Stats()
+		}
+		racers = append(racers, racer)
+	}
+
+	return racers
+}
+
+func (m *Match) racer(playerID string) *racer {
+	for _, r := range m.racers {
+		if r.id == playerID {
+			return r
+		}
+	}
+	return nil
+}
+
+func (m *Match) broadcast(exceptID string) {
+	for _, r := range m.racers {
+		if r.id == exceptID || r.left {
+			continue
+		}
+
+		select {
+		case r.updates <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (m *Match) join(playerID, name string) (<-chan struct{}, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.state != StateWaiting {
+		return nil, ErrMatchStarted
+	}
+
+	r := &racer{
+		id:      playerID,
+		name:    name,
+		updates: make(chan struct{}, 1),
+	}
+	m.racers = append(m.racers, r)
+	m.broadcast(playerID)
+
+	return r.updates, nil
+}
+
+// leave drops the player from the match and reports whether anyone is left.
+// Players who leave a running race stay in the results.
+func (m *Match) leave(playerID string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, r := range m.racers {
+		if r.id != playerID || r.left {
+			continue
+		}
+
+		close(r.updates)
+		if m.state == StateWaiting {
+			m.racers = append(m.racers[:i], m.racers[i+1:]...)
+		} else {
+			r.left = true
+		}
+		break
+	}
+
+	if m.state == StateRacing && m.allOut() {
+		m.state = StateFinished
+	}
+	m.broadcast(playerID)
+
+	for _, r := range m.racers {
+		if !r.left {
+			return true
+		}
+	}
+	return false
+}
+
+// Registry keeps track of open matches. Each player can be in at most one
+// match at a time.
+type Registry struct {
+	mu      sync.Mutex
+	matches map[string]*Match
+	players map[string]*Match
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		matches: map[string]*Match{},
+		players: map[string]*Match{},
+	}
+}
+
+// Create opens a match with a fresh seed and joins the player to it.
+func (reg *Registry) Create(settings Settings, playerID, name string) (*Match, <-chan struct{}, error) {
+	d := game.Difficulty{Width: settings.Width, Height: settings.Height, Mines: settings.Mines}
+	if err := d.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	reg.Leave(playerID)
+
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	m := &Match{
+		Code: room.NewCode(func(code string) bool {
+			_, taken := reg.matches[code]
+			return taken
+		}),
+		Settings: settings,
+		Seed:     rand.Int63(),
+		Start:    game.Coordinate{X: settings.Width / 2, Y: settings.Height / 2},
+	}
+	reg.matches[m.Code] = m
+
+	updates, err := m.join(playerID, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	reg.players[playerID] = m
+
+	return m, updates, nil
+}
+
+func (reg *Registry) Join(code, playerID, name string) (*Match, <-chan struct{}, error) {
+	reg.Leave(playerID)
+
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	m, ok := reg.matches[strings.ToUpper(strings.TrimSpace(code))]
+	if !ok {
+		return nil, nil, ErrMatchNotFound
+	}
+
+	updates, err := m.join(playerID, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	reg.players[playerID] = m
+
+	return m, updates, nil
+}
+
+// Leave removes the player from whatever match they are in. It is safe to
+// call for players that are not in a match.
+func (reg *Registry) Leave(playerID string) {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	m, ok := reg.players[playerID]
+	if !ok {
+		return
+	}
+
+	delete(reg.players, playerID)
+	if !m.leave(playerID) {
+		delete(reg.matches, m.Code)
+	}
+}
@@ -0,0 +1,145 @@
+package versus_test
+
+import (
+	"github.com/jboewer/minesshweeper/game"
+	"github.com/jboewer/minesshweeper/versus"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+var settings = versus.Settings{Width: 9, Height: 9, Mines: 10}
+
+func newMatch(t *testing.T) (*versus.Registry, *versus.Match) {
+	t.Helper()
+
+	reg := versus.NewRegistry()
+	m, _, err := reg.Create(settings, "a", "alice")
+	assert.NoError(t, err)
+	_, _, err = reg.Join(m.Code, "b", "bob")
+	assert.NoError(t, err)
+
+	return reg, m
+}
+
+// clearBoard reveals every safe cell of the player's board.
+func clearBoard(t *testing.T, m *versus.Match, playerID string) {
+	t.Helper()
+
+	g, err := m.Game(playerID)
+	assert.NoError(t, err)
+
+	mines := mineCoordinates(t, m)
+	for y := 0; y < g.GetGridHeight(); y++ {
+		for x := 0; x < g.GetGridWidth(); x++ {
+			if !mines[game.Coordinate{X: x, Y: y}] {
+				m.Reveal(playerID, x, y)
+			}
+		}
+	}
+}
+
+// mineCoordinates finds the mines of the match by probing throwaway games
+// built from the same seed.
+func mineCoordinates(t *testing.T, m *versus.Match) map[game.Coordinate]bool {
+	t.Helper()
+
+	mines := map[game.Coordinate]bool{}
+	for y := 0; y < m.Settings.Height; y++ {
+		for x := 0; x < m.Settings.Width; x++ {
+			probe, _ := game.New(m.Settings.Width, m.Settings.Height, game.WithSeed(m.Seed))
+			probe.PlaceRandomMinesAvoiding(m.Settings.Mines, m.Start)
+			if res, _ := probe.Reveal(x, y); res.Outcome == game.RevealMine {
+				mines[game.Coordinate{X: x, Y: y}] = true
+			}
+		}
+	}
+	assert.Len(t, mines, m.Settings.Mines)
+
+	return mines
+}
+
+func TestMatch_PlayersGetIdenticalBoards(t *testing.T) {
+	_, m := newMatch(t)
+	assert.NoError(t, m.Begin())
+
+	a, err := m.Game("a")
+	assert.NoError(t, err)
+	b, err := m.Game("b")
+	assert.NoError(t, err)
+
+	assert.NotSame(t, a, b)
+	assert.Equal(t, a.Snapshot(), b.Snapshot())
+	assert.Equal(t, game.CellStateRevealed, a.Snapshot().Cell(m.Start.X, m.Start.Y).State)
+}
+
+func TestMatch_FirstToClearWins(t *testing.T) {
+	_, m := newMatch(t)
+	assert.NoError(t, m.Begin())
+
+	clearBoard(t, m, "b")
+
+	assert.Equal(t, versus.StateFinished, m.State())
+	racers := m.Racers()
+	assert.False(t, racers[0].Winner)
+	assert.True(t, racers[1].Winner)
+	assert.Equal(t, game.StateWon, racers[1].State)
+
+	_, err := m.Reveal("a", 0, 0)
+	assert.ErrorIs(t, err, versus.ErrMatchOver)
+}
+
+func TestMatch_ExplosionForfeits(t *testing.T) {
+	_, m := newMatch(t)
+	assert.NoError(t, m.Begin())
+
+	for c := range mineCoordinates(t, m) {
+		m.Reveal("a", c.X, c.Y)
+		break
+	}
+
+	assert.Equal(t, versus.StateRacing, m.State())
+	assert.Equal(t, game.StateLost, m.Racers()[0].State)
+
+	for c := range mineCoordinates(t, m) {
+		m.Reveal("b", c.X, c.Y)
+		break
+	}
+
+	assert.Equal(t, versus.StateFinished, m.State())
+	for _, r := range m.Racers() {
+		assert.False(t, r.Winner)
+	}
+}
+
+func TestMatch_CannotJoinRunningMatch(t *testing.T) {
+	reg, m := newMatch(t)
+	assert.NoError(t, m.Begin())
+
+	_, _, err := reg.Join(m.Code, "c", "carol")
+	assert.ErrorIs(t, err, versus.ErrMatchStarted)
+	assert.ErrorIs(t, m.Begin(), versus.ErrMatchStarted)
+}
+
+func TestMatch_LeavingTheRaceEndsItForTheLastPlayer(t *testing.T) {
+	reg, m := newMatch(t)
+	assert.NoError(t, m.Begin())
+
+	reg.Leave("a")
+	assert.Equal(t, versus.StateRacing, m.State())
+	assert.True(t, m.Racers()[0].Left)
+
+	reg.Leave("b")
+	assert.Equal(t, versus.StateFinished, m.State())
+
+	_, _, err := reg.Join(m.Code, "c", "carol")
+	assert.ErrorIs(t, err, versus.ErrMatchNotFound)
+}
+
+func TestRegistry_CreateValidatesSettings(t *testing.T) {
+	reg := versus.NewRegistry()
+
+	_, _, err := reg.Create(versus.Settings{Width: 0, Height: 9, Mines: 1}, "a", "alice")
+	assert.ErrorIs(t, err, game.ErrInvalidFieldSize)
+	_, _, err = reg.Create(versus.Settings{Width: 3, Height: 3, Mines: 9}, "a", "alice")
+	assert.ErrorIs(t, err, game.ErrInvalidFieldSize)
+}
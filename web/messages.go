@@ -0,0 +1,83 @@
+package web
+
+import (
+	"encoding/json"
+	"github.com/jboewer/minesshweeper/game"
+	"github.com/jboewer/minesshweeper/tui"
+	"net/http"
+)
+
+// message is sent by the browser. Type is one of new, reveal, flag or
+// chord; new takes an optional difficulty, the others a cell.
+type message struct {
+	Type       string `json:"type"`
+	X          int    `json:"x"`
+	Y          int    `json:"y"`
+	Difficulty string `json:"difficulty,omitempty"`
+}
+
+// board is pushed to the browser after every message and every second
+// while the game runs. Cells hold the text the terminal version shows, so
+// mines are only included once the game is lost.
+type board struct {
+	Difficulty string     `json:"difficulty"`
+	Width      int        `json:"width"`
+	Height     int        `json:"height"`
+	Mines      int        `json:"mines"`
+	Flags      int        `json:"flags"`
+	State      string     `json:"state"`
+	ElapsedMs  int64      `json:"elapsed_ms"`
+	Cells      [][]string `json:"cells"`
+	Error      string     `json:"error,omitempty"`
+}
+
+func newBoard(g *game.Game) board {
+	s := g.Snapshot()
+
+	b := board{
+		Difficulty: game.DifficultyOf(s.Width, s.Height, s.MineCount).Name,
+		Width:      s.Width,
+		Height:     s.Height,
+		Mines:      s.MineCount,
+		Flags:      s.FlagCount,
+		State:      stateName(s.State),
+		ElapsedMs:  g.Stats().Elapsed.Milliseconds(),
+		Cells:      make([][]string, s.Height),
+	}
+	for y, row := range s.Cells {
+		b.Cells[y] = make([]string, s.Width)
+		for x, c := range row {
+			b.Cells[y][x] = tui.CellText(c)
+		}
+	}
+
+	return b
+}
+
+func stateName(s game.State) string {
+	switch s {
+	case game.StateWon:
+		return "won"
+	case game.StateLost:
+		return "lost"
+	default:
+		return "playing"
+	}
+}
+
+type leaderboardResponse struct {
+	Difficulty string             `json:"difficulty"`
+	Entries    []leaderboardEntry `json:"entries"`
+}
+
+type leaderboardEntry struct {
+	Name             string  `json:"name"`
+	TimeMillis       int64   `json:"time_ms"`
+	ThreeBVPerSecond float64 `json:"3bv_per_second"`
+	Date             string  `json:"date"`
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,133 @@
+"use strict";
+
+const boardEl = document.getElementById("board");
+const statusEl = document.getElementById("status");
+const errorEl = document.getElementById("error");
+const nameEl = document.getElementById("name");
+const difficultyEl = document.getElementById("difficulty");
+
+let socket = null;
+let board = null;
+
+nameEl.value = localStorage.getItem("name") || "";
+difficultyEl.value = localStorage.getItem("difficulty") || "beginner";
+
+function connect() {
+  if (socket) {
+    socket.onclose = null;
+    socket.close();
+  }
+
+  const scheme = location.protocol === "https:" ? "wss:" : "ws:";
+  const name = nameEl.value.trim();
+  const query = name ? "?name=" + encodeURIComponent(name) : "";
+
+  socket = new WebSocket(scheme + "//" + location.host + "/ws" + query);
+  socket.onopen = () => send({ type: "new", difficulty: difficultyEl.value });
+  socket.onmessage = (event) => render(JSON.parse(event.data));
+  socket.onclose = () => {
+    statusEl.textContent = "Disconnected. Check your nickname and start a new game.";
+  };
+}
+
+function send(message) {
+  if (socket && socket.readyState === WebSocket.OPEN) {
+    socket.send(JSON.stringify(message));
+  }
+}
+
+function render(next) {
+  const finished = board && board.state === "playing" && next.state !== "playing";
+  const resized = !board || board.width !== next.width || board.height !== next.height;
+  board = next;
+
+  errorEl.textContent = board.error || "";
+
+  const seconds = Math.floor(board.elapsed_ms / 1000);
+  const state = { playing: "Playing", won: "Won", lost: "Lost" }[board.state];
+  statusEl.textContent =
+    `${board.difficulty} · Mines left: ${board.mines - board.flags} · ${seconds}s · ${state}`;
+
+  if (resized) {
+    boardEl.replaceChildren();
+    boardEl.style.gridTemplateColumns = `repeat(${board.width}, 2em)`;
+    for (let y = 0; y < board.height; y++) {
+      for (let x = 0; x < board.width; x++) {
+        const cell = document.createElement("div");
+        cell.className = "cell";
+        cell.dataset.x = x;
+        cell.dataset.y = y;
+        boardEl.appendChild(cell);
+      }
+    }
+    loadLeaderboard();
+  }
+
+  for (const cell of boardEl.children) {
+    const text = board.cells[cell.dataset.y][cell.dataset.x];
+    cell.dataset.cell = text;
+    cell.textContent = text;
+  }
+
+  if (finished) {
+    loadLeaderboard();
+  }
+}
+
+boardEl.addEventListener("click", (event) => {
+  const cell = event.target.closest(".cell");
+  if (!cell) {
+    return;
+  }
+
+  const revealed = /^[0-8]$/.test(cell.dataset.cell);
+  send({ type: revealed ? "chord" : "reveal", x: +cell.dataset.x, y: +cell.dataset.y });
+});
+
+boardEl.addEventListener("contextmenu", (event) => {
+  event.preventDefault();
+  const cell = event.target.closest(".cell");
+  if (cell) {
+    send({ type: "flag", x: +cell.dataset.x, y: +cell.dataset.y });
+  }
+});
+
+document.getElementById("settings").addEventListener("submit", (event) => {
+  event.preventDefault();
+  localStorage.setItem("difficulty", difficultyEl.value);
+
+  const name = nameEl.value.trim();
+  if (name !== (localStorage.getItem("name") || "") || !socket || socket.readyState !== WebSocket.OPEN) {
+    localStorage.setItem("name", name);
+    connect();
+    return;
+  }
+  send({ type: "new", difficulty: difficultyEl.value });
+});
+
+async function loadLeaderboard() {
+  if (!board) {
+    return;
+  }
+
+  const size = `${board.width}x${board.height}/${board.mines}`;
+  const response = await fetch("leaderboard?difficulty=" + encodeURIComponent(size));
+  if (!response.ok) {
+    return;
+  }
+  const leaderboard = await response.json();
+
+  document.getElementById("leaderboard-title").textContent = "Leaderboard: " + leaderboard.difficulty;
+  const rows = leaderboard.entries.map((entry, i) => {
+    const row = document.createElement("tr");
+    for (const value of [i + 1, entry.name, (entry.time_ms / 1000).toFixed(1) + "s", entry["3bv_per_second"].toFixed(2), entry.date]) {
+      const td = document.createElement("td");
+      td.textContent = value;
+      row.appendChild(td);
+    }
+    return row;
+  });
+  document.querySelector("#leaderboard tbody").replaceChildren(...rows);
+}
+
+connect();
@@ -0,0 +1,274 @@
+package web
+
+import (
+	"embed"
+	"fmt"
+	"github.com/gorilla/websocket"
+	"github.com/jboewer/minesshweeper/game"
+	"github.com/jboewer/minesshweeper/leaderboard"
+	"github.com/jboewer/minesshweeper/profile"
+	"github.com/jboewer/minesshweeper/tui"
+	"io/fs"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const leaderboardSize = 10
+
+//go:embed static
+var static embed.FS
+
+// NewHandler serves the browser client and the games it plays. Finished
+// games of named players are recorded in results as unverified, next to
+// those of the SSH players.
+func NewHandler(results leaderboard.Store, d game.Difficulty) http.Handler {
+	s := &server{
+		results:    results,
+		difficulty: d,
+	}
+
+	files, err := fs.Sub(static, "static")
+	if err != nil {
+		panic(err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /", http.FileServerFS(files))
+	mux.HandleFunc("GET /colors.css", serveColors)
+	mux.HandleFunc("GET /leaderboard", s.leaderboard)
+	mux.HandleFunc("GET /ws", s.play)
+
+	return mux
+}
+
+type server struct {
+	results    leaderboard.Store
+	difficulty game.Difficulty
+	upgrader   websocket.Upgrader
+}
+
+// PlayerID is how web players are told apart on the leaderboards. Web
+// players only pick a nickname, so it doesn't identify them as reliably as
+// an SSH key.
+func PlayerID(name string) string {
+	return "web:" + strings.ToLower(name)
+}
+
+// play runs a game for a single browser tab. Players who pass a nickname in
+// the name query parameter have their finished games recorded.
+func (s *server) play(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("name")
+	if name != "" {
+		if err := profile.ValidateName(name); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	c := &client{
+		server: s,
+		conn:   conn,
+		name:   name,
+	}
+	if err := c.newGame(message{}); err != nil {
+		log.Println("Could not start game:", err)
+		return
+	}
+	c.run()
+}
+
+// leaderboard returns the best players on the difficulty named in the
+// difficulty query parameter.
+func (s *server) leaderboard(w http.ResponseWriter, r *http.Request) {
+	d, err := game.ParseDifficulty(r.URL.Query().Get("difficulty"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	results, err := s.results.Results()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	entries := []leaderboardEntry{}
+	for _, r := range leaderboard.Best(results, d, leaderboard.ByTime, leaderboardSize) {
+		entries = append(entries, leaderboardEntry{
+			Name:             r.Label(),
+			TimeMillis:       r.Time.Milliseconds(),
+			ThreeBVPerSecond: r.ThreeBVPerSecond(),
+			Date:             r.PlayedAt.Format("2006-01-02"),
+		})
+	}
+
+	writeJSON(w, leaderboardResponse{Difficulty: d.Name, Entries: entries})
+}
+
+// cellTexts lists everything CellText shows, for the style sheet.
+var cellTexts = []string{" ", "0", "1", "2", "3", "4", "5", "6", "7", "8", "F", "M", "B", "X"}
+
+// serveColors styles the cells with the colours of the terminal version.
+func serveColors(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/css; charset=utf-8")
+
+	for _, text := range cellTexts {
+		c := tui.Classic.CellColors(text)
+		fmt.Fprintf(w, ".cell[data-cell=%q] {", text)
+		if c.Foreground != "" {
+			fmt.Fprintf(w, " color: %s;", c.Foreground)
+		}
+		if c.Background != "" {
+			fmt.Fprintf(w, " background: %s;", c.Background)
+		}
+		fmt.Fprintln(w, " }")
+	}
+
+	fmt.Fprintf(w, ".cell:hover { color: %s; background: %s; }\n", tui.Classic.Cursor.Foreground, tui.Classic.Cursor.Background)
+}
+
+// client is the connection to a single browser tab.
+type client struct {
+	server   *server
+	conn     *websocket.Conn
+	name     string
+	game     *game.Game
+	recorded bool
+}
+
+// run answers the client's messages until it disconnects. While a game is
+// running, the board is also pushed every second to keep the timer going.
+func (c *client) run() {
+	messages := make(chan message)
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		defer close(messages)
+		for {
+			var m message
+			if err := c.conn.ReadJSON(&m); err != nil {
+				return
+			}
+			select {
+			case messages <- m:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	if err := c.send(nil); err != nil {
+		return
+	}
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case m, ok := <-messages:
+			if !ok {
+				return
+			}
+			if err := c.send(c.handle(m)); err != nil {
+				return
+			}
+		case <-ticker.C:
+			if c.game.State() != game.StatePlaying {
+				continue
+			}
+			if err := c.send(nil); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (c *client) handle(m message) error {
+	switch m.Type {
+	case "new":
+		return c.newGame(m)
+	case "reveal":
+		if !c.inBounds(m) {
+			return game.ErrOutOfBounds
+		}
+		_, err := c.game.Reveal(m.X, m.Y)
+		c.record()
+		return err
+	case "flag":
+		if !c.inBounds(m) {
+			return game.ErrOutOfBounds
+		}
+		c.game.ToggleFlag(m.X, m.Y)
+		c.record()
+		return nil
+	case "chord":
+		if !c.inBounds(m) {
+			return game.ErrOutOfBounds
+		}
+		_, err := c.game.Chord(m.X, m.Y)
+		c.record()
+		return err
+	default:
+		return fmt.Errorf("unknown message type %q", m.Type)
+	}
+}
+
+func (c *client) newGame(m message) error {
+	d := c.server.difficulty
+	if m.Difficulty != "" {
+		parsed, err := game.ParseDifficulty(m.Difficulty)
+		if err != nil {
+			return err
+		}
+		d = parsed
+	}
+
+	if err := d.CheckSize(); err != nil {
+		return err
+	}
+
+	g, err := game.NewWithDifficulty(d)
+	if err != nil {
+		return err
+	}
+
+	c.game = g
+	c.recorded = false
+	return nil
+}
+
+func (c *client) inBounds(m message) bool {
+	return m.X >= 0 && m.Y >= 0 && m.X < c.game.GetGridWidth() && m.Y < c.game.GetGridHeight()
+}
+
+// record saves the game to the leaderboard once it ends.
+func (c *client) record() {
+	if c.recorded || c.name == "" || c.game.State() == game.StatePlaying {
+		return
+	}
+	c.recorded = true
+
+	r := leaderboard.NewResult(PlayerID(c.name), c.name, c.game)
+	r.Unverified = true
+	if err := c.server.results.Record(r); err != nil {
+		log.Println("Could not record result:", err)
+	}
+}
+
+func (c *client) send(err error) error {
+	b := newBoard(c.game)
+	if err != nil {
+		b.Error = err.Error()
+	}
+	return c.conn.WriteJSON(b)
+}
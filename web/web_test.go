@@ -0,0 +1,176 @@
+package web_test
+
+import (
+	"github.com/gorilla/websocket"
+	"github.com/jboewer/minesshweeper/game"
+	"github.com/jboewer/minesshweeper/leaderboard"
+	"github.com/jboewer/minesshweeper/web"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type board struct {
+	Difficulty string     `json:"difficulty"`
+	Width      int        `json:"width"`
+	Mines      int        `json:"mines"`
+	Flags      int        `json:"flags"`
+	State      string     `json:"state"`
+	Cells      [][]string `json:"cells"`
+	Error      string     `json:"error"`
+}
+
+func newServer(t *testing.T, results leaderboard.Store) *httptest.Server {
+	server := httptest.NewServer(web.NewHandler(results, game.Beginner))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func dial(t *testing.T, server *httptest.Server, query string) *websocket.Conn {
+	t.Helper()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/ws"+query, nil)
+	assert.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return conn
+}
+
+func exchange(t *testing.T, conn *websocket.Conn, m map[string]any) board {
+	t.Helper()
+
+	assert.NoError(t, conn.WriteJSON(m))
+	return receive(t, conn)
+}
+
+func receive(t *testing.T, conn *websocket.Conn) board {
+	t.Helper()
+
+	var b board
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	assert.NoError(t, conn.ReadJSON(&b))
+	return b
+}
+
+func TestPlay(t *testing.T) {
+	server := newServer(t, leaderboard.NewMemoryStore())
+	conn := dial(t, server, "")
+
+	b := receive(t, conn)
+	assert.Equal(t, "Beginner", b.Difficulty)
+	assert.Equal(t, "playing", b.State)
+	assert.Equal(t, " ", b.Cells[0][0])
+
+	t.Run("Flag", func(t *testing.T) {
+		b := exchange(t, conn, map[string]any{"type": "flag", "x": 1, "y": 2})
+
+		assert.Equal(t, 1, b.Flags)
+		assert.Equal(t, "F", b.Cells[2][1])
+	})
+	t.Run("Errors", func(t *testing.T) {
+		b := exchange(t, conn, map[string]any{"type": "reveal", "x": 9, "y": 0})
+		assert.Equal(t, game.ErrOutOfBounds.Error(), b.Error)
+
+		b = exchange(t, conn, map[string]any{"type": "dance"})
+		assert.NotEmpty(t, b.Error)
+
+		b = exchange(t, conn, map[string]any{"type": "new", "difficulty": "1000x1000/1"})
+		assert.NotEmpty(t, b.Error)
+		assert.Equal(t, 9, b.Width)
+	})
+	t.Run("New game", func(t *testing.T) {
+		b := exchange(t, conn, map[string]any{"type": "new", "difficulty": "expert"})
+
+		assert.Empty(t, b.Error)
+		assert.Equal(t, 30, b.Width)
+		assert.Equal(t, 0, b.Flags)
+	})
+	t.Run("Mines stay hidden while playing", func(t *testing.T) {
+		exchange(t, conn, map[string]any{"type": "new", "difficulty": "5x5/20"})
+
+		for i := 0; i < 25; i++ {
+			b = exchange(t, conn, map[string]any{"type": "reveal", "x": i % 5, "y": i / 5})
+			if b.State != "playing" {
+				break
+			}
+			for _, row := range b.Cells {
+				assert.NotContains(t, row, "M")
+			}
+		}
+
+		assert.Equal(t, "lost", b.State)
+	})
+}
+
+func TestRecordsNamedPlayers(t *testing.T) {
+	results := leaderboard.NewMemoryStore()
+	server := newServer(t, results)
+
+	conn := dial(t, server, "?name=alice")
+	receive(t, conn)
+	exchange(t, conn, map[string]any{"type": "new", "difficulty": "2x1/1"})
+	exchange(t, conn, map[string]any{"type": "reveal", "x": 0, "y": 0})
+
+	recorded, _ := results.Results()
+	assert.Len(t, recorded, 1)
+	assert.Equal(t, web.PlayerID("Alice"), recorded[0].PlayerID)
+	assert.Equal(t, "alice", recorded[0].Name)
+	assert.True(t, recorded[0].Unverified)
+
+	anonymous := dial(t, server, "")
+	receive(t, anonymous)
+	exchange(t, anonymous, map[string]any{"type": "new", "difficulty": "2x1/1"})
+	exchange(t, anonymous, map[string]any{"type": "reveal", "x": 0, "y": 0})
+
+	recorded, _ = results.Results()
+	assert.Len(t, recorded, 1)
+}
+
+func TestInvalidName(t *testing.T) {
+	server := newServer(t, leaderboard.NewMemoryStore())
+
+	_, res, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/ws?name=x", nil)
+
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+}
+
+func TestLeaderboard(t *testing.T) {
+	results := leaderboard.NewMemoryStore()
+	results.Record(leaderboard.Result{PlayerID: "a", Name: "alice", Width: 9, Height: 9, Mines: 10, Won: true, Time: 12 * time.Second})
+	results.Record(leaderboard.Result{PlayerID: web.PlayerID("bob"), Name: "bob", Width: 9, Height: 9, Mines: 10, Won: true, Time: 15 * time.Second, Unverified: true})
+	server := newServer(t, results)
+
+	res, err := http.Get(server.URL + "/leaderboard?difficulty=9x9/10")
+	assert.NoError(t, err)
+	defer res.Body.Close()
+	body, _ := io.ReadAll(res.Body)
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Contains(t, string(body), `"difficulty":"Beginner"`)
+	assert.Contains(t, string(body), `"name":"alice"`)
+	assert.Contains(t, string(body), `"name":"bob (unverified)"`)
+	assert.Contains(t, string(body), `"time_ms":12000`)
+}
+
+func TestStaticFiles(t *testing.T) {
+	server := newServer(t, leaderboard.NewMemoryStore())
+
+	for path, want := range map[string]string{
+		"/":           "<title>Minesshweeper</title>",
+		"/app.js":     "new WebSocket",
+		"/colors.css": `.cell[data-cell="1"] { color: #74adf2; }`,
+	} {
+		res, err := http.Get(server.URL + path)
+		assert.NoError(t, err)
+		body, _ := io.ReadAll(res.Body)
+		res.Body.Close()
+
+		assert.Equal(t, http.StatusOK, res.StatusCode, path)
+		assert.Contains(t, string(body), want, path)
+	}
+}